@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+func TestArchiveSegments(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	archive, err := biglog.NewDirArchive(filepath.Join(nl.dataDir, "_archive"))
+	panicOn(err)
+	defer func() { panicOn(os.RemoveAll(filepath.Join(nl.dataDir, "_archive"))) }()
+	ArchiveSegments(archive)(nl)
+
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{CompressionType: CompressionNone})
+	panicOn(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = topic.WriteMessages([]Message{MessageFromPayload(randData(10))})
+		panicOn(err)
+		panicOn(topic.bl.Split())
+	}
+
+	panicOn(topic.bl.Trim())
+	panicOn(topic.bl.Trim())
+	if o := topic.bl.Oldest(); o != 2 {
+		t.Errorf("Expected oldest offset 2, got %d", o)
+	}
+
+	// scanners start from the archived offsets
+	ts, err := topic.NewScanner(0, false)
+	panicOn(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	for expected := int64(0); expected < 3; expected++ {
+		_, o, err2 := ts.Scan(ctx)
+		panicOn(err2)
+		if o != expected {
+			t.Errorf("Scanned offset %d, expected %d", o, expected)
+		}
+	}
+
+	logClose(ts)
+	panicOn(nl.DeleteTopic(topicName, true))
+
+	keys, err := archive.List("")
+	panicOn(err)
+	if len(keys) != 0 {
+		t.Errorf("Archived segments not deleted with the topic: %v", keys)
+	}
+}
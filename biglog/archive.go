@@ -0,0 +1,394 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotArchived is returned by archives when getting a key never put or already deleted.
+var ErrNotArchived = errors.New("biglog: not found in archive")
+
+// Archive stores closed segments outside the BigLog directory, see BigLog.SetArchive.
+// Keys are slash separated, made of the name of the BigLog and the segment file names.
+type Archive interface {
+	// Put stores size bytes read from r under key, replacing any previous content.
+	Put(key string, r io.Reader, size int64) error
+	// Get returns the content stored under key or ErrNotArchived.
+	Get(key string) (io.ReadCloser, error)
+	// List returns the keys starting with prefix in lexical order.
+	List(prefix string) ([]string, error)
+	// Delete removes the content stored under key if any.
+	Delete(key string) error
+}
+
+// SetArchive makes Trim upload the oldest segment to the archive before deleting it. Readers,
+// index readers and scanners of archived offsets fetch their segments back on demand, after
+// which they are part of the BigLog again until trimmed. Deleting the BigLog deletes its
+// archived segments as well.
+func (bl *BigLog) SetArchive(a Archive) error {
+	keys, err := a.List(bl.name + "/")
+	if err != nil {
+		return err
+	}
+
+	var bases []int64
+	for _, key := range keys {
+		var base int64
+		if _, err := fmt.Sscanf(path.Base(key), indexPattern, &base); err == nil {
+			bases = append(bases, base)
+		}
+	}
+
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+
+	bl.amu.Lock()
+	defer bl.amu.Unlock()
+	bl.archive = a
+	bl.archived = bases
+	return nil
+}
+
+// Earliest returns the lowest offset available either in
+// the archive or locally, unlike Oldest which is local only.
+func (bl *BigLog) Earliest() int64 {
+	oldest := bl.Oldest()
+	bl.amu.Lock()
+	defer bl.amu.Unlock()
+	if len(bl.archived) > 0 && bl.archived[0] < oldest {
+		return bl.archived[0]
+	}
+
+	return oldest
+}
+
+// archiveKey returns the key of a segment file in the archive.
+func (bl *BigLog) archiveKey(filePath string) string {
+	return bl.name + "/" + filepath.Base(filePath)
+}
+
+// archiveOldest uploads the oldest segment to the archive unless it was
+// fetched back from it, and returns the segment archived.
+func (bl *BigLog) archiveOldest() (*segment, error) {
+	bl.mu.RLock()
+	if len(bl.segs) < 2 {
+		bl.mu.RUnlock()
+		return nil, ErrLastSegment
+	}
+
+	seg := bl.segs[0]
+	bl.mu.RUnlock()
+
+	if seg.archived {
+		return seg, nil
+	}
+
+	// prevents the segment from being closed while uploading
+	atomic.AddInt32(seg.readers, 1)
+	defer atomic.AddInt32(seg.readers, -1)
+
+	// the index is uploaded last marking the segment as complete
+	data := io.NewSectionReader(seg.dataFile, 0, seg.NdFO)
+	if err := bl.archive.Put(bl.archiveKey(seg.dataPath), data, seg.NdFO); err != nil {
+		return nil, err
+	}
+
+	// the unused part of the index is left out
+	end := int(seg.NiFO) + 2*iw
+	if end > len(seg.index) {
+		end = len(seg.index)
+	}
+
+	index := seg.index[:end]
+	if err := bl.archive.Put(bl.archiveKey(seg.indexPath), bytes.NewReader(index), int64(len(index))); err != nil {
+		return nil, err
+	}
+
+	bl.amu.Lock()
+	bl.archived = append(bl.archived, seg.baseOffset)
+	sort.Slice(bl.archived, func(i, j int) bool { return bl.archived[i] < bl.archived[j] })
+	bl.amu.Unlock()
+
+	return seg, nil
+}
+
+// locate returns the segment holding offset like locateOffset,
+// fetching it back from the archive if it was archived.
+func (bl *BigLog) locate(offset int64) (seg *segment, RO uint32, err error) {
+	seg, RO, err = bl.locateOffset(offset)
+	if err != ErrNotFound || bl.archive == nil || !bl.inArchive(offset) {
+		return seg, RO, err
+	}
+
+	if _, err = bl.restore(offset); err != nil {
+		return nil, 0, err
+	}
+
+	return bl.locateOffset(offset)
+}
+
+// inArchive returns whether offset belongs to an archived segment not fetched back
+// yet, which lies before the next local segment. Restored segments might be older.
+func (bl *BigLog) inArchive(offset int64) bool {
+	bl.amu.Lock()
+	defer bl.amu.Unlock()
+
+	i := sort.Search(len(bl.archived), func(i int) bool { return bl.archived[i] > offset }) - 1
+	if i < 0 {
+		return false
+	}
+
+	base := bl.archived[i]
+	bl.mu.RLock()
+	defer bl.mu.RUnlock()
+	j := sort.Search(len(bl.segs), func(j int) bool { return bl.segs[j].baseOffset >= base })
+	if j < len(bl.segs) && bl.segs[j].baseOffset == base {
+		return false // restored
+	}
+
+	return j < len(bl.segs) && offset < bl.segs[j].baseOffset
+}
+
+// segmentAfter returns the segment following seg or nil if seg is the last one.
+// Archived segments in between are fetched back from the archive.
+func (bl *BigLog) segmentAfter(seg *segment) *segment {
+	segs := bl.segments()
+	i := indexOfSegment(segs, seg.baseOffset)
+	if i < 0 || i == len(segs)-1 {
+		return nil
+	}
+
+	next := absolute(seg.NRO, seg.baseOffset)
+	if bl.archive != nil && segs[i+1].baseOffset > next {
+		restored, err := bl.restore(next)
+		if err == nil {
+			return restored
+		}
+
+		Logger.Printf("error: failed to restore archived offset %d of %s: %s", next, bl.name, err)
+	}
+
+	return segs[i+1]
+}
+
+// restore fetches back from the archive the segment holding offset.
+func (bl *BigLog) restore(offset int64) (*segment, error) {
+	bl.amu.Lock()
+	defer bl.amu.Unlock()
+
+	// restored while waiting for the lock, locateOffset
+	// also finds the offset next to the end of a segment
+	bl.mu.RLock()
+	seg, RO, err := bl.locateOffset(offset)
+	bl.mu.RUnlock()
+	if err == nil && RO < seg.NRO {
+		return seg, nil
+	}
+
+	i := sort.Search(len(bl.archived), func(i int) bool { return bl.archived[i] > offset }) - 1
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	// the segment holding the offset is not archived
+	base := bl.archived[i]
+	bl.mu.RLock()
+	j := indexOfSegment(bl.segs, base)
+	local := j >= 0 && bl.segs[j].baseOffset == base
+	bl.mu.RUnlock()
+	if local {
+		return nil, ErrNotFound
+	}
+
+	dataPath := filepath.Join(bl.dirPath, fmt.Sprintf(dataPattern, base))
+	indexPath := filepath.Join(bl.dirPath, fmt.Sprintf(indexPattern, base))
+	for _, p := range []string{dataPath, indexPath} {
+		if err = bl.download(bl.archiveKey(p), p+tmpSuffix); err != nil {
+			_ = os.Remove(dataPath + tmpSuffix)
+			_ = os.Remove(indexPath + tmpSuffix)
+			return nil, err
+		}
+	}
+
+	// the index is renamed last so that an
+	// interrupted restore is never loaded
+	if err = os.Rename(dataPath+tmpSuffix, dataPath); err == nil {
+		err = os.Rename(indexPath+tmpSuffix, indexPath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	seg, err = loadSegment(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	seg.archived = true
+
+	// the age of the segment is the time of its last write
+	if seg.NiFO >= iw {
+		_, TS, _ := readEntry(seg.index[seg.NiFO-iw:])
+		mtime := time.Unix(int64(TS), 0)
+		_ = os.Chtimes(dataPath, mtime, mtime)
+	}
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	k := indexOfSegment(bl.segs, base) + 1
+	segs := make([]*segment, 0, len(bl.segs)+1)
+	segs = append(segs, bl.segs[:k]...)
+	segs = append(segs, seg)
+	segs = append(segs, bl.segs[k:]...)
+	bl.segs = segs
+
+	Logger.Printf("info: restored archived segment %d of %s", base, bl.name)
+	return seg, nil
+}
+
+// download copies the content stored under key into a new file at path.
+func (bl *BigLog) download(key, path string) error {
+	rc, err := bl.archive.Get(key)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = rc.Close() }()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, rc)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	return err
+}
+
+// deleteArchived deletes all archived segments of the BigLog.
+func (bl *BigLog) deleteArchived() error {
+	keys, err := bl.archive.List(bl.name + "/")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err = bl.archive.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DirArchive is an Archive storing segments in a local directory,
+// usually a mount of a cheaper or network file system.
+type DirArchive struct {
+	dir string
+}
+
+// NewDirArchive returns an archive storing segments in dir, created if it does not exist.
+func NewDirArchive(dir string) (*DirArchive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &DirArchive{dir: dir}, nil
+}
+
+func (a *DirArchive) path(key string) string {
+	return filepath.Join(a.dir, filepath.FromSlash(key))
+}
+
+// Put implements Archive.
+func (a *DirArchive) Put(key string, r io.Reader, size int64) error {
+	p := a.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p+tmpSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(f, r, size)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	if err == nil {
+		err = os.Rename(p+tmpSuffix, p)
+	}
+
+	if err != nil {
+		_ = os.Remove(p + tmpSuffix)
+	}
+
+	return err
+}
+
+// Get implements Archive.
+func (a *DirArchive) Get(key string) (io.ReadCloser, error) {
+	f, err := os.Open(a.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotArchived
+	}
+
+	return f, err
+}
+
+// List implements Archive.
+func (a *DirArchive) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(a.dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || strings.HasSuffix(p, tmpSuffix) {
+			return err
+		}
+
+		rel, err := filepath.Rel(a.dir, p)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+
+	sort.Strings(keys)
+	return keys, err
+}
+
+// Delete implements Archive.
+func (a *DirArchive) Delete(key string) error {
+	err := os.Remove(a.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
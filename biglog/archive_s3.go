@@ -0,0 +1,247 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Archive is an Archive storing segments in a bucket of an S3 compatible object
+// store, addressed in path style (endpoint/bucket/key) and signed with AWS Signature V4.
+type S3Archive struct {
+	// Endpoint is the base URL of the store, e.g. https://s3.eu-west-1.amazonaws.com
+	Endpoint string
+	// Bucket where segments are stored.
+	Bucket string
+	// Prefix is prepended to every key, e.g. "netlog/".
+	Prefix string
+	// Region of the bucket, us-east-1 if empty.
+	Region string
+	// AccessKey and SecretKey are the credentials used to sign requests.
+	AccessKey string
+	SecretKey string
+	// Client is the HTTP client used, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// unsignedPayload skips hashing the uploaded segments, the transport checks their integrity.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Put implements Archive.
+func (a *S3Archive) Put(key string, r io.Reader, size int64) error {
+	req, err := a.request("PUT", a.Prefix+key, nil)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Body = http.NoBody
+	if size > 0 {
+		req.Body = ioutil.NopCloser(io.LimitReader(r, size))
+	}
+
+	res, err := a.do(req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// Get implements Archive.
+func (a *S3Archive) Get(key string) (io.ReadCloser, error) {
+	req, err := a.request("GET", a.Prefix+key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+// List implements Archive.
+func (a *S3Archive) List(prefix string) ([]string, error) {
+	var keys []string
+	query := url.Values{"list-type": {"2"}, "prefix": {a.Prefix + prefix}}
+	for {
+		req, err := a.request("GET", "", query)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := a.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var list struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+
+		err = xml.NewDecoder(res.Body).Decode(&list)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range list.Contents {
+			keys = append(keys, strings.TrimPrefix(c.Key, a.Prefix))
+		}
+
+		if !list.IsTruncated {
+			break
+		}
+
+		query.Set("continuation-token", list.NextContinuationToken)
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete implements Archive.
+func (a *S3Archive) Delete(key string) error {
+	req, err := a.request("DELETE", a.Prefix+key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := a.do(req)
+	if err == ErrNotArchived {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// do sends a signed request mapping error responses to errors.
+func (a *S3Archive) do(req *http.Request) (*http.Response, error) {
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	a.sign(req, time.Now().UTC())
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 300 {
+		return res, nil
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotArchived
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	return nil, fmt.Errorf("biglog: archive %s %s: %s %s", req.Method, req.URL.Path, res.Status, msg)
+}
+
+func (a *S3Archive) request(method, key string, query url.Values) (*http.Request, error) {
+	u, err := url.Parse(strings.TrimSuffix(a.Endpoint, "/") + "/" + a.Bucket + "/" + key)
+	if err != nil {
+		return nil, err
+	}
+
+	u.RawQuery = query.Encode()
+	return http.NewRequest(method, u.String(), nil)
+}
+
+// sign adds the AWS Signature V4 authorization headers to the request.
+func (a *S3Archive) sign(req *http.Request, now time.Time) {
+	region := a.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	headers := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, unsignedPayload, amzDate)
+
+	canonical := strings.Join([]string{
+		req.Method,
+		awsEscape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		headers,
+		strings.Join(signed, ";"),
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + a.SecretKey)
+	for _, s := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		a.AccessKey, scope, strings.Join(signed, ";"), hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(s))
+	return h.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, awsEscape(k, true)+"="+awsEscape(v, true))
+		}
+	}
+
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// awsEscape URI-encodes every byte except the unreserved characters,
+// and slashes unless escaping a query parameter.
+func awsEscape(s string, query bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !query:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,232 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDirArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netlogarchive")
+	panicOn(err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	a, err := NewDirArchive(dir)
+	panicOn(err)
+	testArchive(t, a)
+}
+
+func TestS3Archive(t *testing.T) {
+	s3 := newS3StandIn("bucket")
+	defer s3.Close()
+
+	testArchive(t, &S3Archive{
+		Endpoint:  s3.URL,
+		Bucket:    "bucket",
+		Prefix:    "netlog/",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	})
+
+	if s3.unsigned > 0 {
+		t.Errorf("%d requests without signature", s3.unsigned)
+	}
+}
+
+func TestArchiveRestoreOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netlogarchive")
+	panicOn(err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	a, err := NewDirArchive(dir)
+	panicOn(err)
+
+	bl := tempBigLog()
+	defer logDelete(bl, true)
+
+	// 3 entries per segment
+	for i := 0; i < 10; i++ {
+		if i > 0 && i%3 == 0 {
+			panicOn(bl.Split())
+		}
+
+		_, err = bl.Write([]byte(fmt.Sprintf("e%d", i)))
+		panicOn(err)
+	}
+
+	panicOn(bl.SetArchive(a))
+	panicOn(bl.Trim())
+	panicOn(bl.Trim())
+
+	// restoring the oldest segment leaves a gap of archived offsets
+	for _, o := range []int64{1, 4} {
+		r, _, err := NewReader(bl, o)
+		if err != nil {
+			t.Fatalf("Failed to read archived offset %d after restoring older ones: %s", o, err)
+		}
+
+		panicOn(r.Close())
+	}
+
+	if _, _, err = NewReader(bl, 100); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+// testArchive archives segments of a BigLog and reads them back through a scanner.
+func testArchive(t *testing.T, a Archive) {
+	bl := tempBigLog()
+	defer logDelete(bl, true)
+
+	// 3 entries per segment
+	for i := 0; i < 10; i++ {
+		if i > 0 && i%3 == 0 {
+			panicOn(bl.Split())
+		}
+
+		_, err := bl.Write([]byte(fmt.Sprintf("e%d", i)))
+		panicOn(err)
+	}
+
+	if _, err := a.Get(bl.name + "/none"); err != ErrNotArchived {
+		t.Errorf("Expected ErrNotArchived, got %v", err)
+	}
+
+	panicOn(bl.SetArchive(a))
+	panicOn(bl.Trim())
+	panicOn(bl.Trim())
+
+	if bl.Oldest() != 6 {
+		t.Fatalf("Oldest offset %d after trimming. Expected 6", bl.Oldest())
+	}
+
+	keys, err := a.List(bl.name + "/")
+	panicOn(err)
+	if len(keys) != 4 {
+		t.Errorf("Archived %v", keys)
+	}
+
+	// archived offsets are read again as if never trimmed
+	scan := func(bl *BigLog, from int64) {
+		sc, err2 := NewScanner(bl, from)
+		panicOn(err2)
+		defer func() { panicOn(sc.Close()) }()
+
+		for i := from; i < 10; i++ {
+			if !sc.Scan() {
+				t.Fatalf("Scan stopped before offset %d: %v", i, sc.Err())
+			}
+
+			if sc.Offset() != i || string(sc.Bytes()) != fmt.Sprintf("e%d", i) {
+				t.Errorf("Scanned %q at %d expected offset %d", sc.Bytes(), sc.Offset(), i)
+			}
+		}
+	}
+
+	scan(bl, 1)
+	if info, _ := bl.Info(); info.FirstOffset != 0 || len(info.Segments) != 4 || info.Archived != 2 {
+		t.Errorf("Unexpected info after restoring %+v", info)
+	}
+
+	// restored segments are trimmed without uploading them again
+	panicOn(bl.Trim())
+	panicOn(bl.Trim())
+	if bl.Oldest() != 6 {
+		t.Errorf("Oldest offset %d after trimming restored segments. Expected 6", bl.Oldest())
+	}
+
+	// the archive survives restarts
+	panicOn(bl.Close())
+	bl, err = Open(bl.dirPath)
+	panicOn(err)
+	panicOn(bl.SetArchive(a))
+	scan(bl, 4)
+
+	if _, _, err = NewReader(bl, 100); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	panicOn(bl.Delete(false))
+	keys, err = a.List(bl.name + "/")
+	panicOn(err)
+	if len(keys) > 0 {
+		t.Errorf("Archived segments left after delete %v", keys)
+	}
+}
+
+// s3StandIn is an in-memory stand-in of an S3 compatible store.
+type s3StandIn struct {
+	*httptest.Server
+	bucket   string
+	mu       sync.Mutex
+	objects  map[string][]byte
+	unsigned int
+}
+
+func newS3StandIn(bucket string) *s3StandIn {
+	s := &s3StandIn{bucket: bucket, objects: make(map[string][]byte)}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+		r.Header.Get("X-Amz-Date") == "" {
+		s.unsigned++
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/"+s.bucket+"/")
+	switch {
+	case r.Method == "PUT":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil || int64(len(data)) != r.ContentLength {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = data
+	case r.Method == "GET" && key == "":
+		var res struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []struct{ Key string }
+		}
+
+		var keys []string
+		for k := range s.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+			res.Contents = append(res.Contents, struct{ Key string }{k})
+		}
+		panicOn(xml.NewEncoder(w).Encode(res))
+	case r.Method == "GET":
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == "DELETE":
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
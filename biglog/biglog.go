@@ -34,6 +34,9 @@ var (
 	// ErrBusy is returned when there are active readers or watchers while trying
 	// to close/delete the biglog
 	ErrBusy = errors.New("biglog: resource busy")
+
+	// ErrReadOnly is returned when modifying a BigLog opened with OpenReadOnly.
+	ErrReadOnly = errors.New("biglog: read-only")
 )
 
 // Option is the type of function used to set internal parameters
@@ -49,8 +52,9 @@ func BufioWriter(size int) Option {
 
 // BigLog is the main structure TODO ...
 type BigLog struct {
-	name    string
-	dirPath string
+	name     string
+	dirPath  string
+	readOnly bool
 
 	mu        sync.RWMutex
 	segs      []*segment
@@ -62,6 +66,10 @@ type BigLog struct {
 
 	rmu     sync.Mutex
 	readers atomic.Value
+
+	amu      sync.Mutex
+	archive  Archive
+	archived []int64 // base offsets of the archived segments
 }
 
 // SetOpts sets options after BigLog has been created
@@ -82,12 +90,18 @@ type readerMap map[io.Closer]struct{}
 // In the index each write will consumed an entry, independently of how many
 // offsets are contained.
 func Create(dirPath string, maxIndexEntries int) (*BigLog, error) {
+	return CreateAt(dirPath, maxIndexEntries, 0)
+}
+
+// CreateAt creates a new biglog like Create where the first
+// written entry gets `baseOffset` instead of zero.
+func CreateAt(dirPath string, maxIndexEntries int, baseOffset int64) (*BigLog, error) {
 	err := os.Mkdir(dirPath, 0755)
 	if err != nil {
 		return nil, err
 	}
 
-	seg, err := createSegment(dirPath, maxIndexEntries, 0)
+	seg, err := createSegment(dirPath, maxIndexEntries, baseOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +122,23 @@ func Create(dirPath string, maxIndexEntries int) (*BigLog, error) {
 // ErrInvalid is returned if there are no index files within dirPath.
 // ErrLoadSegment is returned if a segment can not be loaded.
 func Open(dirPath string) (*BigLog, error) {
+	return open(dirPath, false)
+}
+
+// OpenReadOnly loads a BigLog from disk like Open but without modifying its
+// files, meant for inspecting logs in use or damaged. Interrupted rewrites and
+// partial writes are left as found and every write returns ErrReadOnly.
+func OpenReadOnly(dirPath string) (*BigLog, error) {
+	return open(dirPath, true)
+}
+
+func open(dirPath string, readOnly bool) (*BigLog, error) {
+	if !readOnly {
+		if err := recoverRewrites(dirPath); err != nil {
+			return nil, err
+		}
+	}
+
 	dirfs, err := ioutil.ReadDir(dirPath)
 	if err != nil {
 		return nil, err
@@ -126,9 +157,10 @@ func Open(dirPath string) (*BigLog, error) {
 
 	dirPath, _ = filepath.Abs(dirPath)
 	bl := &BigLog{
-		name:    filepath.Base(dirPath),
-		dirPath: dirPath,
-		segs:    make([]*segment, 0),
+		name:     filepath.Base(dirPath),
+		dirPath:  dirPath,
+		segs:     make([]*segment, 0),
+		readOnly: readOnly,
 	}
 
 	// initialize hot segment type for atomic load
@@ -139,7 +171,7 @@ func Open(dirPath string) (*BigLog, error) {
 	sort.Strings(indexes)
 	var seg *segment
 	for _, index := range indexes {
-		seg, err = loadSegment(filepath.Join(dirPath, index))
+		seg, err = openSegment(filepath.Join(dirPath, index), readOnly)
 		if err != nil {
 			return nil, err
 		}
@@ -148,9 +180,10 @@ func Open(dirPath string) (*BigLog, error) {
 		hotSeg = seg
 	}
 
-	err = hotSeg.healthCheckPartialWrite()
-	if err != nil {
-		return nil, err
+	if !readOnly {
+		if err = hotSeg.healthCheckPartialWrite(); err != nil {
+			return nil, err
+		}
 	}
 
 	err = bl.setHotSeg(hotSeg)
@@ -197,7 +230,22 @@ func (bl *BigLog) WriteN(b []byte, n int) (written int, err error) {
 	return bl.writeN(b, uint32(n))
 }
 
+// Append writes a batch of n entries from b like WriteN
+// and returns the offset assigned to the first of them.
+func (bl *BigLog) Append(b []byte, n int) (offset int64, err error) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	offset = bl.latest() + 1
+	_, err = bl.writeN(b, uint32(n))
+	return offset, err
+}
+
 func (bl *BigLog) writeN(b []byte, n uint32) (written int, err error) {
+	if bl.readOnly {
+		return 0, ErrReadOnly
+	}
+
 	err = bl.splitIfFull()
 	if err != nil {
 		return 0, err
@@ -215,6 +263,10 @@ func (bl *BigLog) ReadFrom(src io.Reader) (written int64, err error) {
 	bl.mu.Lock()
 	defer bl.mu.Unlock()
 
+	if bl.readOnly {
+		return 0, ErrReadOnly
+	}
+
 	err = bl.splitIfFull()
 	if err != nil {
 		return 0, err
@@ -256,7 +308,7 @@ func (bl *BigLog) latest() int64 {
 
 	// If there is only one segment and it's empty
 	if len(bl.segs) == 1 && hotSeg.NRO == 1 {
-		return -1 // no data
+		return hotSeg.baseOffset - 1 // no data
 	}
 
 	// latest = next available -1
@@ -281,6 +333,10 @@ func (bl *BigLog) Split() error {
 }
 
 func (bl *BigLog) split() (err error) {
+	if bl.readOnly {
+		return ErrReadOnly
+	}
+
 	maxIndexEntries := int(bl.hotSeg.Load().(*segment).indexSize / iw)
 	seg, err := createSegment(bl.dirPath, maxIndexEntries, bl.latest()+1)
 	if err != nil {
@@ -333,6 +389,11 @@ func (bl *BigLog) setHotSeg(seg *segment) (err error) {
 			hotSeg.writer = hotSeg.dataFile
 		}
 
+		// a later Sync only covers the new hot segment
+		if sErr := hotSeg.Sync(); err == nil {
+			err = sErr
+		}
+
 		close(hotSeg.notify)
 	}
 
@@ -344,8 +405,20 @@ func (bl *BigLog) setHotSeg(seg *segment) (err error) {
 	return err
 }
 
-// Trim removes the oldest segment from the biglog.
+// Trim removes the oldest segment from the biglog,
+// uploading it first to the archive if there is one.
 func (bl *BigLog) Trim() (err error) {
+	if bl.readOnly {
+		return ErrReadOnly
+	}
+
+	var archived *segment
+	if bl.archive != nil {
+		if archived, err = bl.archiveOldest(); err != nil {
+			return err
+		}
+	}
+
 	bl.mu.Lock()
 	defer bl.mu.Unlock()
 
@@ -353,6 +426,11 @@ func (bl *BigLog) Trim() (err error) {
 		return ErrLastSegment
 	}
 
+	// rewritten or restored while uploading
+	if bl.archive != nil && bl.segs[0] != archived {
+		return ErrSegmentBusy
+	}
+
 	if err = bl.segs[0].Delete(false); err != nil {
 		return err
 	}
@@ -414,6 +492,10 @@ func (bl *BigLog) Delete(force bool) (err error) {
 }
 
 func (bl *BigLog) delete(force bool) (err error) {
+	if bl.readOnly {
+		return ErrReadOnly
+	}
+
 	err = bl.close(force)
 	if err != nil && !force {
 		return err
@@ -430,6 +512,12 @@ func (bl *BigLog) delete(force bool) (err error) {
 		}
 	}
 
+	if bl.archive != nil {
+		if err = bl.deleteArchived(); err != nil && !force {
+			return err
+		}
+	}
+
 	return os.RemoveAll(bl.dirPath)
 }
 
@@ -180,3 +180,103 @@ func TestNewBigLog(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestCreateAt(t *testing.T) {
+	bl, err := biglog.CreateAt(filepath.Join(os.TempDir(), fmt.Sprintf("biglogtest-%d", rand.Int63())), 100, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bl.Oldest() != 42 || bl.Latest() != 41 {
+		t.Errorf("Unexpected empty offsets oldest=%d latest=%d", bl.Oldest(), bl.Latest())
+	}
+
+	_, err = bl.WriteN([]byte("first"), 2)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if bl.Latest() != 43 {
+		t.Errorf("Unexpected latest offset %d", bl.Latest())
+	}
+
+	r, _, err := biglog.NewReader(bl, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = bl.Sync()
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(r, buf)
+	if err != nil || string(buf) != "first" {
+		t.Errorf("Unexpected read %q err %v", buf, err)
+	}
+
+	err = r.Close()
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = bl.Delete(false)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	bl, err := biglog.Create(filepath.Join(os.TempDir(), fmt.Sprintf("biglogtest-%d", rand.Int63())), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err = bl.Delete(false); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	_, err = bl.WriteN([]byte("first"), 2)
+	if err == nil {
+		_, err = bl.Write([]byte("second"))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro, err := biglog.OpenReadOnly(bl.DirPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ro.Write([]byte("third")); err != biglog.ErrReadOnly {
+		t.Errorf("Expected ErrReadOnly writing, got %v", err)
+	}
+
+	if err = ro.Split(); err != biglog.ErrReadOnly {
+		t.Errorf("Expected ErrReadOnly splitting, got %v", err)
+	}
+
+	entries, err := ro.IndexEntries(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 ||
+		entries[0].RO != 1 || entries[0].ODelta != 2 || entries[0].Size != 5 ||
+		entries[1].Offset != 2 || entries[1].DFO != entries[0].DFO+5 || entries[1].Size != 6 {
+		t.Errorf("Unexpected index entries %+v %+v", entries[0], entries[len(entries)-1])
+	}
+
+	if _, err = ro.IndexEntries(1); err != biglog.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err = ro.Close(); err != nil {
+		t.Error(err)
+	}
+}
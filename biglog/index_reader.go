@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -61,7 +62,7 @@ type Entry struct {
 
 // NewIndexReader returns an IndexReader that will start reading from a given offset
 func NewIndexReader(bl *BigLog, from int64) (r *IndexReader, ret int64, err error) {
-	seg, RO, err := bl.locateOffset(from)
+	seg, RO, err := bl.locate(from)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -123,6 +124,11 @@ func (r *IndexReader) ReadEntries(n int) (entries []*Entry, err error) {
 // it's lower precision than ReadEntries but better suited for streaming since it does not need to allocate
 // an Entry struct for every entry read in the index.
 func (r *IndexReader) ReadSection(maxOffsets, maxBytes int64) (is *IndexSection, err error) {
+	return r.readSection(math.MaxInt64, maxOffsets, maxBytes)
+}
+
+// readSection reads a section like ReadSection holding at most maxEntries entries.
+func (r *IndexReader) readSection(maxEntries, maxOffsets, maxBytes int64) (is *IndexSection, err error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -133,7 +139,7 @@ func (r *IndexReader) ReadSection(maxOffsets, maxBytes int64) (is *IndexSection,
 	is = &IndexSection{}
 
 	var firstIter = true
-	for {
+	for is.EDelta < maxEntries {
 
 		err = r.jumpSeg()
 		if err != nil {
@@ -143,6 +149,19 @@ func (r *IndexReader) ReadSection(maxOffsets, maxBytes int64) (is *IndexSection,
 		RO, _, dFO := readEntry(r.seg.index[r.iFO:])
 		NRO, _, NdFO := readEntry(r.seg.index[r.iFO+iw:])
 
+		// entries without data left by rewrites are sections on their own
+		// so that every other section holds contiguous offsets
+		if NdFO == dFO {
+			if firstIter {
+				is.Offset = absolute(RO, r.seg.baseOffset)
+				is.EDelta = 1
+				is.ODelta = int64(NRO - RO)
+				r.iFO += iw
+			}
+
+			break
+		}
+
 		// check offset limit
 		if is.ODelta+int64(NRO-RO) > maxOffsets {
 			if firstIter {
@@ -196,14 +215,7 @@ func (r *IndexReader) jumpSeg() error {
 
 // we need to scan all segments again since the slice could have changed since the last read
 func (r *IndexReader) nextSeg() (seg *segment) {
-
-	segs := r.bl.segments()
-	i := indexOfSegment(segs, r.seg.baseOffset)
-	if i < 0 || i == len(segs)-1 {
-		return nil
-	}
-
-	return segs[i+1]
+	return r.bl.segmentAfter(r.seg)
 }
 
 // Close frees up the segments and renders the reader unusable
@@ -4,7 +4,10 @@
 
 package biglog
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
 // Info holds all BigLog meta data
 type Info struct {
@@ -15,6 +18,10 @@ type Info struct {
 	LatestOffset int64      `json:"latest_offset"`
 	Segments     []*SegInfo `json:"segments"`
 	ModTime      time.Time  `json:"mod_time"`
+	Archived     int        `json:"archived_segments,omitempty"`
+	// Readers and Watchers are the number of active readers and watchers of the BigLog.
+	Readers  int `json:"readers"`
+	Watchers int `json:"watchers"`
 }
 
 // Info returns an Info struct with all information about the BigLog.
@@ -25,8 +32,14 @@ func (bl *BigLog) Info() (*Info, error) {
 		Path:         bl.dirPath,
 		FirstOffset:  bl.Oldest(),
 		LatestOffset: bl.Latest(),
+		Readers:      len(bl.readers.Load().(readerMap)),
+		Watchers:     len(bl.watchers.Load().(watcherMap)),
 	}
 
+	bl.amu.Lock()
+	inf.Archived = len(bl.archived)
+	bl.amu.Unlock()
+
 	segs := bl.segments()
 	for k := range segs {
 		si, err := segs[k].Info()
@@ -40,3 +53,78 @@ func (bl *BigLog) Info() (*Info, error) {
 
 	return inf, nil
 }
+
+// DataSizeFrom returns the size of the data stored from the entry holding `offset` until
+// the latest offset, zero if `offset` is after it. Offsets before the oldest one count from it.
+func (bl *BigLog) DataSizeFrom(offset int64) (int64, error) {
+	bl.mu.RLock()
+	defer bl.mu.RUnlock()
+
+	if offset > bl.latest() {
+		return 0, nil
+	}
+
+	if oldest := bl.segs[0].baseOffset; offset < oldest {
+		offset = oldest
+	}
+
+	i := indexOfSegment(bl.segs, offset)
+	seg := bl.segs[i]
+	l, err := seg.Lookup(relative(offset, seg.baseOffset))
+	if err != nil {
+		return 0, err
+	}
+
+	size := seg.NdFO - l.dFO
+	for _, s := range bl.segs[i+1:] {
+		size += s.NdFO - headerSize
+	}
+
+	return size, nil
+}
+
+// IndexEntry is an entry of a segment index as stored on disk.
+type IndexEntry struct {
+	// RO is the offset of the entry relative to the base offset of the segment.
+	RO uint32 `json:"ro"`
+	// Offset is the absolute offset of the entry.
+	Offset    int64     `json:"offset"`
+	Timestamp time.Time `json:"timestamp"`
+	// DFO is the position of the entry in the data file.
+	DFO int64 `json:"dfo"`
+	// ODelta is the number of offsets held by the entry.
+	ODelta int `json:"odelta"`
+	// Size is the size of the data of the entry, 0 if removed by a rewrite.
+	Size int64 `json:"size"`
+}
+
+// IndexEntries returns all entries in the index of the segment
+// with base offset `base` or ErrNotFound if there is no such segment.
+func (bl *BigLog) IndexEntries(base int64) ([]*IndexEntry, error) {
+	segs := bl.segments()
+	i := indexOfSegment(segs, base)
+	if i < 0 || segs[i].baseOffset != base {
+		return nil, ErrNotFound
+	}
+
+	seg := segs[i]
+	atomic.AddInt32(seg.readers, 1)
+	defer atomic.AddInt32(seg.readers, -1)
+
+	var entries []*IndexEntry
+	NiFO := atomic.LoadUint32(&seg.NiFO)
+	for iFO := uint32(0); iFO < NiFO; iFO += iw {
+		RO, TS, dFO := readEntry(seg.index[iFO:])
+		NRO, _, NdFO := readEntry(seg.index[iFO+iw:])
+		entries = append(entries, &IndexEntry{
+			RO:        RO,
+			Offset:    absolute(RO, base),
+			Timestamp: time.Unix(int64(TS), 0),
+			DFO:       dFO,
+			ODelta:    int(NRO - RO),
+			Size:      NdFO - dFO,
+		})
+	}
+
+	return entries, nil
+}
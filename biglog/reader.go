@@ -27,7 +27,7 @@ type Reader struct {
 // NewReader returns a Reader that will start reading from a given offset
 // the reader implements the io.ReaderCloser interface
 func NewReader(bl *BigLog, from int64) (r *Reader, ret int64, err error) {
-	seg, RO, err := bl.locateOffset(from)
+	seg, RO, err := bl.locate(from)
 	if err != nil {
 		return nil, -1, err
 	}
@@ -89,14 +89,7 @@ func (r *Reader) Read(b []byte) (n int, err error) {
 // we need to scan all segments every time since
 // the slice could have changed since the last read
 func (r *Reader) nextSeg() (seg *segment) {
-
-	segs := r.bl.segments()
-	i := indexOfSegment(segs, r.seg.baseOffset)
-	if i < 0 || i == len(segs)-1 {
-		return nil
-	}
-
-	return segs[i+1]
+	return r.bl.segmentAfter(r.seg)
 }
 
 // Close frees up the segments and renders the reader unusable
@@ -0,0 +1,482 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EntryParser reads the entry at the start of r, a section of a segment data file running until
+// its end, and returns the size and the number of offsets of the entry, or an error if no valid
+// entry starts there. BigLog does not know the format of the data written, so repairing a
+// segment needs a parser of the data, see BigLog.RepairSegment.
+type EntryParser func(r *io.SectionReader) (size int64, n int, err error)
+
+// quarantineDir is the folder within the BigLog where bad data ranges are moved to.
+const quarantineDir = "quarantine"
+
+// RepairReport describes the changes made repairing a segment.
+type RepairReport struct {
+	// Segment is the base offset of the segment.
+	Segment int64 `json:"segment"`
+	// Repaired is false if the segment was found healthy and left untouched.
+	Repaired bool `json:"repaired"`
+	// Entries is the number of entries in the repaired index.
+	Entries int `json:"entries"`
+	// Rebuilt is the number of entries missing in the index found walking the data file.
+	Rebuilt int `json:"rebuilt"`
+	// Truncated is the number of bytes discarded at the end of the data file.
+	Truncated int64 `json:"truncated_bytes"`
+	// Lost is the number of offsets left without data, or to be written again in the hot segment.
+	Lost int64 `json:"lost_offsets"`
+	// Quarantined lists the bad data moved to the quarantine folder of the BigLog.
+	Quarantined []*QuarantinedRange `json:"quarantined,omitempty"`
+	// Error is set when the segment could not be repaired.
+	Error string `json:"error,omitempty"`
+}
+
+// QuarantinedRange is a range of bad data moved out of a segment.
+type QuarantinedRange struct {
+	// Offset is the first offset of the data, ODelta the number of offsets lost.
+	Offset int64 `json:"offset"`
+	ODelta int64 `json:"odelta"`
+	// DFO is the position of the data in the original data file and Size its length.
+	DFO  int64  `json:"dfo"`
+	Size int64  `json:"size"`
+	Path string `json:"path"`
+}
+
+// RepairSegment checks the index of the segment starting at offset base against its data file,
+// walking every entry with parse, and rewrites the segment if any inconsistency is found:
+//
+//   - entries missing in the index are rebuilt from the data file.
+//   - data is truncated at the first entry that can not be parsed or does not match its index entry.
+//   - with quarantine set, bad data is moved to the quarantine folder of the BigLog, and the entries
+//     of the index following it are kept if valid. The offsets of the bad data are left without data.
+//
+// Closed segments keep all their offsets, those without data are indexed as after a rewrite.
+// Truncated offsets of the hot segment are assigned again to the next writes. Writes are blocked
+// during the repair and ErrSegmentBusy is returned if the segment is being read.
+func (bl *BigLog) RepairSegment(base int64, parse EntryParser, quarantine bool) (rep *RepairReport, err error) {
+	if bl.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	i := indexOfSegment(bl.segs, base)
+	if i < 0 || bl.segs[i].baseOffset != base {
+		return nil, ErrNotFound
+	}
+
+	seg := bl.segs[i]
+	if seg.IsBusy() {
+		return nil, ErrSegmentBusy
+	}
+
+	hot := seg == bl.hotSeg.Load().(*segment)
+	if hot {
+		if err = bl.sync(); err != nil {
+			return nil, err
+		}
+	}
+
+	fi, err := seg.dataFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &repairer{
+		seg:   seg,
+		parse: parse,
+		size:  fi.Size(),
+		hot:   hot,
+		pos:   headerSize,
+		dFO:   headerSize,
+		RO:    1,
+		TS:    seg.createdTS,
+		rep:   &RepairReport{Segment: base},
+	}
+
+	// closed segments end where the next one starts
+	r.end = seg.NRO
+	if !hot {
+		r.end = relative(bl.segs[i+1].baseOffset, base)
+	}
+
+	if quarantine {
+		r.qDir = filepath.Join(bl.dirPath, quarantineDir)
+	}
+
+	if err = r.check(); err != nil {
+		return nil, err
+	}
+
+	if !r.changed() {
+		return r.rep, nil
+	}
+
+	Logger.Printf("warn: repairing segment %d of %s", base, bl.name)
+	if err = r.write(); err != nil {
+		return nil, err
+	}
+
+	// the same swap of rewritten segments, without holding
+	// readers since none can open the segment meanwhile
+	if err = completeSwap(seg.indexPath); err != nil {
+		return nil, err
+	}
+
+	repaired, err := loadSegment(seg.indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if hot {
+		if err = bl.setHotSeg(repaired); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = seg.Close(); err != nil {
+		Logger.Printf("error: failed to close replaced segment %d of %s: %s", base, bl.name, err)
+	}
+
+	segs := make([]*segment, len(bl.segs))
+	copy(segs, bl.segs)
+	segs[i] = repaired
+	bl.segs = segs
+
+	r.rep.Repaired = true
+	return r.rep, nil
+}
+
+// repairer holds the state of a segment being repaired.
+type repairer struct {
+	seg   *segment
+	parse EntryParser
+	qDir  string // quarantine folder, empty if not quarantining
+	hot   bool   // whether the segment is the hot one
+	size  int64  // size of the original data file
+	end   uint32 // relative offset following the segment, or the old NRO of the hot segment
+
+	pos     int64      // position in the original data file
+	entries []byte     // index entries of the repaired segment
+	kept    [][2]int64 // ranges of the original data kept
+	RO      uint32     // next relative offset
+	dFO     int64      // next position in the repaired data file
+	TS      uint32     // timestamp of the last entry
+	rep     *RepairReport
+}
+
+// oldEntry is an entry of the original index with the position and offset following it.
+type oldEntry struct {
+	RO, NRO, TS uint32
+	dFO, NdFO   int64
+}
+
+// oldEntries reads the entries of the original index until the first empty one.
+func (r *repairer) oldEntries() (entries []oldEntry) {
+	index := r.seg.index
+	for iFO := 0; iFO+2*iw <= len(index); iFO += iw {
+		RO, TS, dFO := readEntry(index[iFO:])
+		NRO, _, NdFO := readEntry(index[iFO+iw:])
+		if NRO == 0 {
+			break
+		}
+
+		entries = append(entries, oldEntry{RO: RO, NRO: NRO, TS: TS, dFO: dFO, NdFO: NdFO})
+	}
+
+	return entries
+}
+
+// check walks the original index and data building the repaired index.
+func (r *repairer) check() error {
+	old := r.oldEntries()
+	for k := 0; k < len(old); k++ {
+		e := old[k]
+		if e.RO != r.RO || e.dFO != r.pos || e.NdFO < e.dFO || e.NRO <= e.RO || e.NRO > r.end {
+			// the rest of the index is not usable
+			break
+		}
+
+		// offsets without data
+		if e.NdFO == e.dFO {
+			r.index(e.NRO-e.RO, 0, e.TS)
+			continue
+		}
+
+		if r.valid(e) {
+			r.keep(e.NdFO-e.dFO, e.NRO-e.RO, e.TS)
+			continue
+		}
+
+		if r.qDir == "" {
+			return r.truncate()
+		}
+
+		// the next valid entry of the index after the bad data
+		next := -1
+		for j := k + 1; j < len(old) && next < 0; j++ {
+			if old[j].dFO > r.pos && old[j].NdFO > old[j].dFO && old[j].NRO <= r.end && r.valid(old[j]) {
+				next = j
+			}
+		}
+
+		if next < 0 {
+			return r.truncate()
+		}
+
+		n := old[next].RO - r.RO
+		if err := r.quarantine(old[next].dFO-r.pos, n); err != nil {
+			return err
+		}
+
+		r.rep.Lost += int64(n)
+		r.index(n, 0, r.TS)
+		r.pos = old[next].dFO
+
+		k = next - 1
+	}
+
+	return r.rebuild()
+}
+
+// valid checks that the data of an entry of the original index parses into as many offsets.
+func (r *repairer) valid(e oldEntry) bool {
+	if e.NdFO > r.size {
+		return false
+	}
+
+	var n int
+	for pos := e.dFO; pos < e.NdFO; {
+		size, en, err := r.parse(io.NewSectionReader(r.seg.dataFile, pos, e.NdFO-pos))
+		if err != nil || size <= 0 {
+			return false
+		}
+
+		pos += size
+		n += en
+		if pos == e.NdFO {
+			return n == int(e.NRO-e.RO)
+		}
+	}
+
+	return false
+}
+
+// rebuild indexes the data following the last valid entry of the original index.
+func (r *repairer) rebuild() error {
+	for r.pos < r.size {
+		size, n, err := r.parse(io.NewSectionReader(r.seg.dataFile, r.pos, r.size-r.pos))
+		if err != nil || size <= 0 || r.pos+size > r.size || n < 1 || !r.hot && r.RO+uint32(n) > r.end {
+			return r.truncate()
+		}
+
+		r.keep(size, uint32(n), r.TS)
+		r.rep.Rebuilt++
+	}
+
+	return r.fill()
+}
+
+// truncate discards the data from the current position, moving it to quarantine if enabled.
+func (r *repairer) truncate() error {
+	if r.pos < r.size {
+		r.rep.Truncated = r.size - r.pos
+		if r.qDir != "" {
+			var lost uint32
+			if r.end > r.RO {
+				lost = r.end - r.RO
+			}
+
+			if err := r.quarantine(r.size-r.pos, lost); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.fill()
+}
+
+// fill indexes the offsets of a closed segment left without data.
+func (r *repairer) fill() error {
+	if r.RO < r.end {
+		r.rep.Lost += int64(r.end - r.RO)
+		if !r.hot {
+			r.index(r.end-r.RO, 0, r.TS)
+		}
+	}
+
+	r.rep.Entries = len(r.entries) / iw
+	return nil
+}
+
+// quarantine copies size bytes of bad data at the current position,
+// holding n offsets, to the quarantine folder.
+func (r *repairer) quarantine(size int64, n uint32) error {
+	if err := os.MkdirAll(r.qDir, 0755); err != nil {
+		return err
+	}
+
+	q := &QuarantinedRange{
+		Offset: absolute(r.RO, r.seg.baseOffset),
+		ODelta: int64(n),
+		DFO:    r.pos,
+		Size:   size,
+		Path:   filepath.Join(r.qDir, fmt.Sprintf("%020d-%d.data", r.seg.baseOffset, r.pos)),
+	}
+
+	f, err := os.OpenFile(q.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, io.NewSectionReader(r.seg.dataFile, r.pos, size))
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	Logger.Printf("warn: quarantined %d bytes of segment %d in %s", size, r.seg.baseOffset, q.Path)
+	r.rep.Quarantined = append(r.rep.Quarantined, q)
+	return nil
+}
+
+// keep indexes an entry of size bytes and n offsets at the current position.
+func (r *repairer) keep(size int64, n, TS uint32) {
+	if k := len(r.kept) - 1; k >= 0 && r.kept[k][1] == r.pos {
+		r.kept[k][1] += size
+	} else {
+		r.kept = append(r.kept, [2]int64{r.pos, r.pos + size})
+	}
+
+	r.index(n, size, TS)
+	r.pos += size
+}
+
+// index appends an entry of n offsets and size bytes to the repaired index.
+func (r *repairer) index(n uint32, size int64, TS uint32) {
+	if n == 0 {
+		return
+	}
+
+	entry := make([]byte, iw)
+	writeEntry(entry, r.RO, r.dFO)
+	writeEntryTS(entry, TS)
+	r.entries = append(r.entries, entry...)
+
+	r.RO += n
+	r.dFO += size
+	r.TS = TS
+}
+
+// changed returns whether the repaired segment differs from the original one.
+func (r *repairer) changed() bool {
+	if r.rep.Truncated > 0 || r.rep.Rebuilt > 0 || len(r.rep.Quarantined) > 0 {
+		return true
+	}
+
+	// same index entries including the next offset
+	n := len(r.entries)
+	if n+iw > len(r.seg.index) {
+		return true
+	}
+
+	RO, _, dFO := readEntry(r.seg.index[n:])
+	return !bytes.Equal(r.entries, r.seg.index[:n]) || RO != r.RO || dFO != r.dFO
+}
+
+// write writes the swap files of the repaired segment.
+func (r *repairer) write() (err error) {
+	seg := r.seg
+	defer func() {
+		if err != nil {
+			_ = os.Remove(seg.dataPath + tmpSuffix)
+			_ = os.Remove(seg.indexPath + tmpSuffix)
+		}
+	}()
+
+	data, err := os.OpenFile(seg.dataPath+tmpSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	// the original header keeps the creation time
+	_, err = io.Copy(data, io.NewSectionReader(seg.dataFile, 0, headerSize))
+	for _, k := range r.kept {
+		if err == nil {
+			_, err = io.Copy(data, io.NewSectionReader(seg.dataFile, k[0], k[1]-k[0]))
+		}
+	}
+
+	if err == nil {
+		err = data.Sync()
+	}
+
+	if cErr := data.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// keep the modification time used by age based retention
+	if fi, err := seg.dataFile.Stat(); err == nil {
+		_ = os.Chtimes(seg.dataPath+tmpSuffix, fi.ModTime(), fi.ModTime())
+	}
+
+	// the index keeps its size so the hot segment can go on being written,
+	// the next relative offset is followed by empty entries as in new segments
+	size := len(seg.index)
+	if min := len(r.entries) + 2*iw; size < min {
+		size = min
+	}
+
+	index := make([]byte, size)
+	copy(index, r.entries)
+	writeEntry(index[len(r.entries):], r.RO, r.dFO)
+	writeEntryTS(index[len(r.entries):], uint32(time.Now().Unix()))
+
+	f, err := os.OpenFile(seg.indexPath+tmpSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(index); err == nil {
+		err = f.Sync()
+	}
+
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the index is renamed last so that its swap file
+	// marks the repair as complete in case of a crash
+	if err = os.Rename(seg.dataPath+tmpSuffix, seg.dataPath+swapSuffix); err != nil {
+		return err
+	}
+
+	return os.Rename(seg.indexPath+tmpSuffix, seg.indexPath+swapSuffix)
+}
@@ -0,0 +1,309 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrHotSegment is returned when trying to rewrite the segment currently being written.
+var ErrHotSegment = errors.New("biglog: hot segment can't be rewritten")
+
+const (
+	tmpSuffix  = ".tmp"  // files of a segment being rewritten
+	swapSuffix = ".swap" // files of a rewritten segment ready to replace the original
+)
+
+// SegmentWriter writes the replacement of a closed segment, see BigLog.RewriteSegment.
+// Entries keep the offsets they had in the original segment. The offsets skipped
+// between entries are left as gaps, indexed as entries without data which readers
+// and scanners return as empty entries holding the skipped offsets.
+type SegmentWriter struct {
+	seg     *segment // segment being replaced
+	entries []byte   // index entries written so far
+	data    *os.File
+	w       *bufio.Writer
+	NRO     uint32 // next relative offset written
+	NdFO    int64  // next data file offset
+}
+
+// RewriteSegment replaces the closed segment starting at offset base with the entries
+// written by fn. The segment is left untouched if fn returns an error. Any reader fn
+// opens on the segment must be closed before returning, otherwise ErrSegmentBusy is
+// returned, as it is when the segment is being read by anybody else at that point.
+func (bl *BigLog) RewriteSegment(base int64, fn func(w *SegmentWriter) error) (err error) {
+	if bl.readOnly {
+		return ErrReadOnly
+	}
+
+	bl.mu.RLock()
+	var seg *segment
+	if i := indexOfSegment(bl.segs, base); i >= 0 && bl.segs[i].baseOffset == base {
+		seg = bl.segs[i]
+	}
+	hotSeg := bl.hotSeg.Load().(*segment)
+	bl.mu.RUnlock()
+
+	if seg == nil {
+		return ErrNotFound
+	}
+
+	if seg == hotSeg {
+		return ErrHotSegment
+	}
+
+	w, err := newSegmentWriter(seg)
+	if err != nil {
+		return err
+	}
+
+	defer w.discard()
+
+	if err = fn(w); err != nil {
+		return err
+	}
+
+	if err = w.finish(); err != nil {
+		return err
+	}
+
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+	return bl.swapSegment(seg)
+}
+
+func newSegmentWriter(seg *segment) (*SegmentWriter, error) {
+	// copy the original header to keep the creation time
+	sh := make([]byte, headerSize)
+	if _, err := seg.ReadAt(sh, 0); err != nil {
+		return nil, err
+	}
+
+	data, err := os.OpenFile(seg.dataPath+tmpSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &SegmentWriter{
+		seg:  seg,
+		data: data,
+		w:    bufio.NewWriter(data),
+		NRO:  1,
+		NdFO: headerSize,
+	}
+
+	_, err = w.w.Write(sh)
+	return w, err
+}
+
+// Segment returns the range of offsets [first, next) held by the segment being rewritten.
+func (w *SegmentWriter) Segment() (first, next int64) {
+	return w.seg.baseOffset, absolute(w.seg.NRO, w.seg.baseOffset)
+}
+
+// WriteN writes b as an entry of n offsets starting at offset. Entries must be written
+// in order and fit within the offsets of the original segment.
+func (w *SegmentWriter) WriteN(offset int64, b []byte, n int) error {
+	first, next := w.Segment()
+	if n < 1 || offset < first || offset+int64(n) > next {
+		return ErrROInvalid
+	}
+
+	RO := relative(offset, w.seg.baseOffset)
+	if RO < w.NRO {
+		return ErrROInvalid
+	}
+
+	if RO > w.NRO {
+		w.index(RO-w.NRO, 0)
+	}
+
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+
+	w.index(uint32(n), int64(len(b)))
+	return nil
+}
+
+// index appends an entry of n offsets and length bytes at the next relative offset
+// keeping the timestamp of the original entry holding that offset.
+func (w *SegmentWriter) index(n uint32, length int64) {
+	var TS uint32
+	if l, _ := w.seg.Lookup(w.NRO); l != nil {
+		TS = l.TS
+	}
+
+	entry := make([]byte, iw)
+	writeEntry(entry, w.NRO, w.NdFO)
+	writeEntryTS(entry, TS)
+	w.entries = append(w.entries, entry...)
+
+	w.NRO += n
+	w.NdFO += length
+}
+
+// finish leaves the trailing offsets as a gap and
+// persists the data and index of the new segment.
+func (w *SegmentWriter) finish() error {
+	if w.NRO < w.seg.NRO {
+		w.index(w.seg.NRO-w.NRO, 0)
+	}
+
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+
+	if err := w.data.Sync(); err != nil {
+		return err
+	}
+
+	if err := w.data.Close(); err != nil {
+		return err
+	}
+
+	w.data = nil
+
+	// keep the modification time used by age based retention
+	if fi, err := w.seg.dataFile.Stat(); err == nil {
+		_ = os.Chtimes(w.seg.dataPath+tmpSuffix, fi.ModTime(), fi.ModTime())
+	}
+
+	// next relative offset followed by an empty entry
+	// marking the end of the index as in new segments
+	tail := make([]byte, 2*iw)
+	writeEntry(tail, w.NRO, w.NdFO)
+
+	index, err := os.OpenFile(w.seg.indexPath+tmpSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	if _, err = index.Write(append(w.entries, tail...)); err == nil {
+		err = index.Sync()
+	}
+
+	if cErr := index.Close(); err == nil {
+		err = cErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the index is renamed last so that its swap file
+	// marks the rewrite as complete in case of a crash
+	if err = os.Rename(w.seg.dataPath+tmpSuffix, w.seg.dataPath+swapSuffix); err != nil {
+		return err
+	}
+
+	return os.Rename(w.seg.indexPath+tmpSuffix, w.seg.indexPath+swapSuffix)
+}
+
+// discard removes the files of a rewrite not swapped in.
+func (w *SegmentWriter) discard() {
+	if w.data != nil {
+		_ = w.data.Close()
+	}
+
+	_ = os.Remove(w.seg.dataPath + tmpSuffix)
+	_ = os.Remove(w.seg.indexPath + tmpSuffix)
+
+	// swap files are left when the segment was not replaced, once
+	// the data file is moved in place they are needed to recover
+	if _, err := os.Stat(w.seg.dataPath + swapSuffix); err == nil {
+		_ = os.Remove(w.seg.indexPath + swapSuffix)
+		_ = os.Remove(w.seg.dataPath + swapSuffix)
+	}
+}
+
+// swapSegment replaces the segment with its rewritten files, bl.mu must be held.
+// The files are moved while the original segment is still open, if that fails it
+// keeps serving the original data and the swap files left are completed by
+// recoverRewrites on the next start.
+func (bl *BigLog) swapSegment(old *segment) error {
+	i := indexOfSegment(bl.segs, old.baseOffset)
+	if i < 0 || bl.segs[i] != old {
+		return ErrNotFound
+	}
+
+	if old.IsBusy() {
+		return ErrSegmentBusy
+	}
+
+	if err := completeSwap(old.indexPath); err != nil {
+		Logger.Printf("alert: failed to replace segment %s: %s", old.indexPath, err)
+		return err
+	}
+
+	if err := old.Close(); err != nil {
+		Logger.Printf("error: failed to close replaced segment %s: %s", old.indexPath, err)
+	}
+
+	seg, err := loadSegment(old.indexPath)
+	if err != nil {
+		return err
+	}
+
+	segs := make([]*segment, len(bl.segs))
+	copy(segs, bl.segs)
+	segs[i] = seg
+	bl.segs = segs
+
+	return nil
+}
+
+// completeSwap moves the swap files of a rewritten segment in place.
+func completeSwap(indexPath string) error {
+	dataPath := strings.TrimSuffix(indexPath, ".index") + ".data"
+	err := os.Rename(dataPath+swapSuffix, dataPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Rename(indexPath+swapSuffix, indexPath)
+}
+
+// recoverRewrites completes or discards the segment rewrites interrupted by a crash.
+func recoverRewrites(dirPath string) error {
+	dirfs, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range dirfs {
+		path := filepath.Join(dirPath, f.Name())
+		switch {
+		case strings.HasSuffix(path, tmpSuffix):
+			err = os.Remove(path)
+		case strings.HasSuffix(path, ".index"+swapSuffix):
+			Logger.Printf("warn: completing rewrite of %s", path)
+			err = completeSwap(strings.TrimSuffix(path, swapSuffix))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// data swap files left are from incomplete rewrites
+	swaps, err := filepath.Glob(filepath.Join(dirPath, "*.data"+swapSuffix))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range swaps {
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package biglog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestRewriteSegment(t *testing.T) {
+	bl := tempBigLog()
+	defer logDelete(bl, true)
+
+	for i := 0; i < 10; i++ {
+		_, err := bl.Write([]byte(fmt.Sprintf("e%d", i)))
+		panicOn(err)
+	}
+
+	panicOn(bl.Split())
+	_, err := bl.Write([]byte("e10"))
+	panicOn(err)
+
+	if err = bl.RewriteSegment(10, nil); err != ErrHotSegment {
+		t.Errorf("Expected ErrHotSegment, got %v", err)
+	}
+
+	// a reader on the segment blocks the swap
+	r, _, err := NewReader(bl, 0)
+	panicOn(err)
+	err = bl.RewriteSegment(0, func(w *SegmentWriter) error { return nil })
+	if err != ErrSegmentBusy {
+		t.Errorf("Expected ErrSegmentBusy, got %v", err)
+	}
+	panicOn(r.Close())
+
+	err = bl.RewriteSegment(0, func(w *SegmentWriter) error {
+		if first, next := w.Segment(); first != 0 || next != 10 {
+			t.Errorf("Unexpected segment range [%d, %d)", first, next)
+		}
+
+		panicOn(w.WriteN(2, []byte("e2"), 1))
+		panicOn(w.WriteN(5, []byte("e5e6"), 2))
+		if err2 := w.WriteN(4, []byte("e4"), 1); err2 != ErrROInvalid {
+			t.Errorf("Expected ErrROInvalid, got %v", err2)
+		}
+		return w.WriteN(9, []byte("e9"), 1)
+	})
+	panicOn(err)
+
+	expected := []struct {
+		offset int64
+		delta  int
+		data   string
+	}{
+		{0, 2, ""}, {2, 1, "e2"}, {3, 2, ""}, {5, 2, "e5e6"},
+		{7, 2, ""}, {9, 1, "e9"}, {10, 1, "e10"},
+	}
+
+	check := func(bl *BigLog) {
+		sc, err2 := NewScanner(bl, 0)
+		panicOn(err2)
+		defer func() { panicOn(sc.Close()) }()
+
+		for _, exp := range expected {
+			if !sc.Scan() {
+				t.Fatalf("Scan stopped before offset %d: %v", exp.offset, sc.Err())
+			}
+
+			if sc.Offset() != exp.offset || sc.ODelta() != exp.delta || string(sc.Bytes()) != exp.data {
+				t.Errorf("Scanned %d/%d %q expected %d/%d %q",
+					sc.Offset(), sc.ODelta(), sc.Bytes(), exp.offset, exp.delta, exp.data)
+			}
+		}
+
+		// offsets keep their position
+		r2, ret, err2 := NewReader(bl, 6)
+		if err2 != ErrEmbeddedOffset || ret != 5 {
+			t.Errorf("Unexpected reader at %d: %v", ret, err2)
+		}
+		buf := make([]byte, 4)
+		_, err2 = r2.Read(buf)
+		panicOn(err2)
+		if string(buf) != "e5e6" {
+			t.Errorf("Read %q at offset 5", buf)
+		}
+		panicOn(r2.Close())
+	}
+
+	check(bl)
+
+	// gaps are streamed on their own
+	st, err := NewStreamer(bl, 0)
+	panicOn(err)
+	for _, exp := range []struct{ offset, delta, size int64 }{{0, 2, 0}, {2, 1, 2}, {3, 2, 0}, {5, 2, 4}} {
+		d, err2 := st.Get(100, 1024)
+		panicOn(err2)
+		if d.Offset() != exp.offset || d.OffsetDelta() != exp.delta || d.Size() != exp.size {
+			t.Errorf("Unexpected delta %d/%d %d bytes", d.Offset(), d.OffsetDelta(), d.Size())
+		}
+		_, _ = ioutil.ReadAll(d)
+		panicOn(st.Put(d))
+	}
+	panicOn(st.Close())
+
+	panicOn(bl.Close())
+	bl, err = Open(bl.dirPath)
+	panicOn(err)
+	check(bl)
+}
+
+func TestSwapSegmentError(t *testing.T) {
+	bl := tempBigLog()
+	defer logDelete(bl, true)
+
+	_, err := bl.Write([]byte("data"))
+	panicOn(err)
+	panicOn(bl.Split())
+
+	// the data file is moved in place but not the index
+	seg := bl.segments()[0]
+	panicOn(ioutil.WriteFile(seg.dataPath+swapSuffix, []byte("junk"), 0666))
+	panicOn(os.Mkdir(seg.indexPath+swapSuffix, 0755))
+	defer func() { panicOn(os.Remove(seg.indexPath + swapSuffix)) }()
+
+	bl.mu.Lock()
+	err = bl.swapSegment(seg)
+	bl.mu.Unlock()
+	if err == nil {
+		t.Fatal("Expected error swapping segment")
+	}
+
+	if bl.segments()[0] != seg {
+		t.Errorf("Original segment replaced after a failed swap")
+	}
+
+	if _, err = os.Stat(seg.indexPath + swapSuffix); err != nil {
+		t.Errorf("Index swap file removed after a failed swap: %s", err)
+	}
+
+	// the original segment keeps serving its data
+	r, _, err := NewReader(bl, 0)
+	panicOn(err)
+	defer func() { panicOn(r.Close()) }()
+	buf := make([]byte, 4)
+	_, err = r.Read(buf)
+	panicOn(err)
+	if string(buf) != "data" {
+		t.Errorf("Read %q", buf)
+	}
+}
+
+func TestRecoverRewrites(t *testing.T) {
+	bl := tempBigLog()
+	defer logDelete(bl, true)
+
+	_, err := bl.Write([]byte("data"))
+	panicOn(err)
+	panicOn(bl.Split())
+
+	seg := bl.segments()[0]
+	for _, path := range []string{seg.dataPath + tmpSuffix, seg.indexPath + tmpSuffix, seg.dataPath + swapSuffix} {
+		panicOn(ioutil.WriteFile(path, []byte("junk"), 0666))
+	}
+
+	panicOn(bl.Close())
+	bl, err = Open(bl.dirPath)
+	panicOn(err)
+
+	for _, path := range []string{seg.dataPath + tmpSuffix, seg.indexPath + tmpSuffix, seg.dataPath + swapSuffix} {
+		if _, err = os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Incomplete rewrite file %s not removed", path)
+		}
+	}
+
+	r, _, err := NewReader(bl, 0)
+	panicOn(err)
+	defer func() { panicOn(r.Close()) }()
+	buf := make([]byte, 4)
+	_, err = r.Read(buf)
+	panicOn(err)
+	if string(buf) != "data" {
+		t.Errorf("Read %q", buf)
+	}
+}
@@ -87,6 +87,8 @@ type segment struct {
 	NiFO       uint32 // next offset in the index file (iFO of NRO)
 
 	notify chan struct{} // channel to notify write events
+
+	archived bool // fetched back from the archive
 }
 
 // createSegment creates and loads a new segment at the given dirPath.
@@ -124,6 +126,17 @@ func createSegment(dirPath string, maxIndexEntries int, baseOffset int64) (*segm
 // indexPattern or the corresponding index or data file can not be opened
 // or memory mapped.
 func loadSegment(indexPath string) (*segment, error) {
+	return openSegment(indexPath, false)
+}
+
+// openSegment loads a segment like loadSegment, opening
+// its files and mapping its index read-only if requested.
+func openSegment(indexPath string, readOnly bool) (*segment, error) {
+	indexFlag, dataFlag, prot := os.O_RDWR, os.O_RDWR|os.O_APPEND, mmapProtFlags
+	if readOnly {
+		indexFlag, dataFlag, prot = os.O_RDONLY, os.O_RDONLY, gommap.PROT_READ
+	}
+
 	dirPath, indexName := filepath.Split(indexPath)
 
 	var baseOffset int64
@@ -133,7 +146,7 @@ func loadSegment(indexPath string) (*segment, error) {
 		return nil, ErrLoadSegment
 	}
 
-	indexFile, err := os.OpenFile(indexPath, os.O_RDWR, 0666)
+	indexFile, err := os.OpenFile(indexPath, indexFlag, 0666)
 	if err != nil {
 		Logger.Printf("error: '%s' %s", indexPath, err)
 		return nil, ErrLoadSegment
@@ -141,7 +154,7 @@ func loadSegment(indexPath string) (*segment, error) {
 
 	dataName := fmt.Sprintf(dataPattern, baseOffset)
 	dataPath := filepath.Join(dirPath, dataName)
-	dataFile, err := os.OpenFile(dataPath, os.O_RDWR|os.O_APPEND, 0666)
+	dataFile, err := os.OpenFile(dataPath, dataFlag, 0666)
 	if err != nil {
 		Logger.Printf("error: '%s' %s", indexPath, err)
 		return nil, ErrLoadSegment
@@ -166,7 +179,7 @@ func loadSegment(indexPath string) (*segment, error) {
 		notify:     make(chan struct{}, 1),
 	}
 
-	seg.index, err = gommap.Map(seg.indexFile.Fd(), mmapProtFlags, mmapMapFlags)
+	seg.index, err = gommap.Map(seg.indexFile.Fd(), prot, mmapMapFlags)
 	if err != nil {
 		Logger.Printf("error: can't MMAP index: %s", err)
 		_ = seg.indexFile.Close()
@@ -389,7 +402,7 @@ func (s *segment) Lookup(RO uint32) (l *lookupRes, err error) {
 	// it should be an exact match if there was no batching.
 	maxIFO := (RO - 1) * iw
 	// if the highest possible entry is bigger than the index itself bail.
-	if len(s.index) < int(maxIFO) {
+	if len(s.index) < int(maxIFO)+iw {
 		return s.searchRO(RO)
 	}
 	maxRO, TS, dFO := readEntry(s.index[maxIFO:])
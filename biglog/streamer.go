@@ -6,6 +6,8 @@ package biglog
 
 import (
 	"io"
+	"io/ioutil"
+	"math"
 	"sync"
 )
 
@@ -85,9 +87,24 @@ func NewStreamer(bl *BigLog, from int64) (s *Streamer, err error) {
 // for either limit, either ErrNeedMoreOffsets or ErrNeedMoreBytes is returned.
 // IMPORTANT: The StreamDelta must be "Put" back before a new one can issued.
 func (st *Streamer) Get(maxOffsets, maxBytes int64) (delta *StreamDelta, err error) {
+	return st.get(math.MaxInt64, maxOffsets, maxBytes)
+}
+
+// GetEntry returns a StreamDelta like Get holding a single stored entry, so the
+// boundaries of the entries are kept by streaming them one delta at a time.
+func (st *Streamer) GetEntry(maxOffsets, maxBytes int64) (delta *StreamDelta, err error) {
+	return st.get(1, maxOffsets, maxBytes)
+}
+
+func (st *Streamer) get(maxEntries, maxOffsets, maxBytes int64) (delta *StreamDelta, err error) {
 	st.mu.Lock()
 
-	sec, err := st.ir.ReadSection(maxOffsets, maxBytes)
+	sec, err := st.ir.readSection(maxEntries, maxOffsets, maxBytes)
+	// a section cut short by the end of the log is still valid
+	if err == io.EOF && sec != nil && sec.EDelta > 0 {
+		err = nil
+	}
+
 	if err != nil {
 		st.mu.Unlock()
 		return nil, err
@@ -107,13 +124,32 @@ func (st *Streamer) Get(maxOffsets, maxBytes int64) (delta *StreamDelta, err err
 
 // Put must be called once StreamDelta has been successfully read
 // so the reader can advance and a new StreamDelta can be issued.
+// If the delta was not read entirely the remaining data is skipped.
 func (st *Streamer) Put(delta *StreamDelta) (err error) {
 	defer st.mu.Unlock()
+
+	if delta.sent < delta.size {
+		return st.correctPartialDelta(delta)
+	}
+
 	return nil
 }
 
+// correctPartialDelta discards the unread data of a delta
+// so the reader is positioned at the start of the next one.
 func (st *Streamer) correctPartialDelta(delta *StreamDelta) error {
-	// TODO implement when defined.
-	Logger.Print("error: correctPartialDelta not implemented")
-	return nil
+	_, err := io.Copy(ioutil.Discard, delta.reader)
+	return err
+}
+
+// Close frees up the underlying readers rendering the Streamer unusable.
+func (st *Streamer) Close() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if err := st.r.Close(); err != nil {
+		return err
+	}
+
+	return st.ir.Close()
 }
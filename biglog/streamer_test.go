@@ -5,6 +5,7 @@
 package biglog
 
 import (
+	"io"
 	"io/ioutil"
 	"testing"
 )
@@ -95,3 +96,119 @@ func TestStreamer(t *testing.T) {
 	}
 
 }
+
+func TestStreamerPartialDelta(t *testing.T) {
+	var entrySize = 100
+
+	bl := setupData(entrySize)
+	defer logDelete(bl, true)
+	streamer, err := NewStreamer(bl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// read only half of the first entry
+	delta, err := streamer.Get(1, int64(entrySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = delta.Read(make([]byte, entrySize/2))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = streamer.Put(delta)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// the whole remaining log fits, the section ends at EOF
+	delta, err = streamer.Get(100, int64(100*entrySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if delta.Offset() != 1 {
+		t.Errorf("offset %d instead of %d", delta.Offset(), 1)
+	}
+
+	if delta.OffsetDelta() != 44 {
+		t.Errorf("offset delta %d instead of %d", delta.OffsetDelta(), 44)
+	}
+
+	data, err := ioutil.ReadAll(delta)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(data) != 8*entrySize {
+		t.Errorf("delta data size of %d instead of %d", len(data), 8*entrySize)
+	}
+
+	err = streamer.Put(delta)
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = streamer.Get(100, int64(100*entrySize))
+	if err != io.EOF {
+		t.Errorf("expected EOF got %v", err)
+	}
+
+	err = streamer.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStreamerGetEntry(t *testing.T) {
+	var entrySize = 100
+
+	bl := setupData(entrySize)
+	defer logDelete(bl, true)
+	streamer, err := NewStreamer(bl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every delta holds a single entry even if many fit
+	for i := int64(1); i < 10; i++ {
+		delta, err := streamer.GetEntry(100, int64(100*entrySize))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if delta.EntryDelta() != 1 {
+			t.Errorf("entry delta %d instead of %d", delta.EntryDelta(), 1)
+		}
+
+		if delta.OffsetDelta() != i {
+			t.Errorf("offset delta %d instead of %d", delta.OffsetDelta(), i)
+		}
+
+		data, err := ioutil.ReadAll(delta)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if len(data) != entrySize {
+			t.Errorf("delta data size of %d instead of %d", len(data), entrySize)
+		}
+
+		err = streamer.Put(delta)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	_, err = streamer.GetEntry(100, int64(100*entrySize))
+	if err != io.EOF {
+		t.Errorf("expected EOF got %v", err)
+	}
+
+	err = streamer.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
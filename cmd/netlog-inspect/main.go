@@ -0,0 +1,267 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// netlog-inspect looks inside the segments of a topic directory without
+// starting a server. Topics are opened read-only, so it is safe to use
+// on the data folder of a running server or on damaged topics.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"comail.io/go/colog"
+	"github.com/ninibe/netlog"
+	"github.com/ninibe/netlog/biglog"
+)
+
+const usage = `Usage: netlog-inspect <command> [flags] <topic dir>
+
+Commands:
+  segments  list the segments of the topic
+  index     dump the index entries of the segments
+  messages  print the messages in an offset range
+  check     check the integrity of the topic
+
+Run netlog-inspect <command> -h for the flags of each command.
+`
+
+func main() {
+	colog.Register()
+	colog.SetMinLevel(colog.LWarning)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "segments":
+		segments(args)
+	case "index":
+		index(args)
+	case "messages":
+		messages(args)
+	case "check":
+		check(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// open parses the flags of a command and opens the topic directory given as argument.
+func open(fs *flag.FlagSet, args []string) *biglog.BigLog {
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: netlog-inspect %s [flags] <topic dir>\n", fs.Name())
+		fs.PrintDefaults()
+	}
+
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	bl, err := biglog.OpenReadOnly(fs.Arg(0))
+	fatalOn(err)
+	return bl
+}
+
+func segments(args []string) {
+	fs := flag.NewFlagSet("segments", flag.ExitOnError)
+	bl := open(fs, args)
+	defer logClose(bl)
+
+	info, err := bl.Info()
+	fatalOn(err)
+
+	fmt.Printf("topic %s offsets %d-%d, %d bytes\n", info.Name, info.FirstOffset, info.LatestOffset, info.DiskSize)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "FIRST OFFSET\tDATA SIZE\tDISK SIZE\tMODIFIED\t")
+	for _, si := range info.Segments {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t\n", si.FirstOffset, si.DataSize, si.DiskSize, si.ModTime.Format(time.RFC3339))
+	}
+
+	fatalOn(w.Flush())
+}
+
+func index(args []string) {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	segment := fs.Int64("segment", -1, "First offset of the segment to dump, all segments if negative")
+	bl := open(fs, args)
+	defer logClose(bl)
+
+	info, err := bl.Info()
+	fatalOn(err)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "SEGMENT\tRO\tOFFSET\tTIMESTAMP\tDFO\tODELTA\tSIZE\t")
+	for _, si := range info.Segments {
+		if *segment >= 0 && si.FirstOffset != *segment {
+			continue
+		}
+
+		entries, err := bl.IndexEntries(si.FirstOffset)
+		fatalOn(err)
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%d\t%d\t%d\t\n", si.FirstOffset, e.RO, e.Offset,
+				e.Timestamp.Format(time.RFC3339), e.DFO, e.ODelta, e.Size)
+		}
+	}
+
+	fatalOn(w.Flush())
+}
+
+func messages(args []string) {
+	fs := flag.NewFlagSet("messages", flag.ExitOnError)
+	from := fs.Int64("from", -1, "First offset printed, the oldest if negative")
+	to := fs.Int64("to", -1, "Last offset printed, the latest if negative")
+	max := fs.Int("payload", 64, "Maximum number of payload bytes printed per message")
+	hex := fs.Bool("hex", false, "Print payloads in hexadecimal")
+	bl := open(fs, args)
+	defer logClose(bl)
+
+	if *from < 0 {
+		*from = bl.Oldest()
+	}
+
+	if *to < 0 {
+		*to = bl.Latest()
+	}
+
+	sc, err := biglog.NewScanner(bl, *from)
+	if err != nil && err != biglog.ErrEmbeddedOffset {
+		fatalOn(err)
+	}
+
+	defer logClose(sc)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "OFFSET\tVERSION\tCOMPRESSION\tTIMESTAMP\tKEY\tPAYLOAD")
+	for sc.Scan() && sc.Offset() <= *to {
+		entry := netlog.Message(sc.Bytes())
+		if len(entry) == 0 {
+			fmt.Fprintf(w, "%d-%d\t\t\t\t\t(removed by compaction)\n", sc.Offset(), sc.Offset()+int64(sc.ODelta())-1)
+			continue
+		}
+
+		// message-sets are printed along with their messages
+		comp := entry.Compression()
+		msgs := []netlog.Message{entry}
+		if sc.ODelta() > 1 || comp != netlog.CompressionDefault {
+			fmt.Fprintf(w, "%d-%d\t%d\t%s\t\t\t(message-set of %d bytes)\n", sc.Offset(),
+				sc.Offset()+int64(sc.ODelta())-1, entry.Version(), compressionName(comp), len(entry))
+			if msgs, err = netlog.UnpackDelta(entry); err != nil {
+				fmt.Fprintf(w, "%d\t\t\t\t\t(corrupt message-set: %s)\n", sc.Offset(), err)
+				continue
+			}
+		}
+
+		for k, m := range msgs {
+			offset := sc.Offset() + int64(k)
+			if offset < *from || offset > *to {
+				continue
+			}
+
+			var ts string
+			if t := m.Timestamp(); !t.IsZero() {
+				ts = t.Format(time.RFC3339Nano)
+			}
+
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", offset, m.Version(), compressionName(m.Compression()),
+				ts, strconv.Quote(string(m.Key())), printable(m.Payload(), *max, *hex))
+		}
+	}
+
+	fatalOn(w.Flush())
+	fatalOn(sc.Err())
+}
+
+func check(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	from := fs.Int64("from", -1, "First offset checked, the oldest if negative")
+	progress := fs.Bool("progress", false, "Print the progress of the check every second")
+	bl := open(fs, args)
+	defer logClose(bl)
+
+	if *from < 0 {
+		*from = bl.Oldest()
+	}
+
+	ic, err := netlog.NewLogIntegrityChecker(bl, *from)
+	fatalOn(err)
+	if *progress {
+		ic.OnProgress(time.Second, func(p netlog.IntegrityProgress) {
+			fmt.Fprintf(os.Stderr, "checked %d offsets up to %d of %d, %d errors\n", p.Checked, p.Offset, p.Latest, p.Errors)
+		})
+	}
+
+	iErrs := ic.Check(context.Background())
+	logClose(ic)
+
+	if len(iErrs) == 0 {
+		fmt.Printf("topic %s: no integrity errors found\n", filepath.Base(bl.DirPath()))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "OFFSET\tODELTA\tTYPE\tEXPECTED\tACTUAL")
+	for _, e := range iErrs {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", e.Offset, e.ODelta, e.Type, e.Expected, e.Actual)
+	}
+
+	fatalOn(w.Flush())
+	os.Exit(1)
+}
+
+func compressionName(comp netlog.CompressionType) string {
+	switch comp {
+	case netlog.CompressionDefault:
+		return "-"
+	case netlog.CompressionNone:
+		return "none"
+	case netlog.CompressionGzip:
+		return "gzip"
+	case netlog.CompressionSnappy:
+		return "snappy"
+	}
+
+	return strconv.Itoa(int(comp))
+}
+
+// printable returns up to max bytes of a payload quoted or in hexadecimal.
+func printable(p []byte, max int, hex bool) string {
+	var more string
+	if len(p) > max {
+		p, more = p[:max], fmt.Sprintf("... (%d bytes)", len(p))
+	}
+
+	if hex {
+		return fmt.Sprintf("%x%s", p, more)
+	}
+
+	return strconv.Quote(string(p)) + more
+}
+
+func logClose(c io.Closer) {
+	if err := c.Close(); err != nil {
+		log.Printf("error: %s", err)
+	}
+}
+
+func fatalOn(err error) {
+	if err != nil {
+		log.Fatalf("alert: %s\n", err)
+	}
+}
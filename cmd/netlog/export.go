@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ninibe/netlog"
+)
+
+// runExport implements `netlog export`, writing a topic export into a file or stdout.
+// The server must not be running on the same data folder, use the HTTP endpoint instead.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dir := fs.String("dir", "./data", "Data folder")
+	topic := fs.String("topic", "", "Topic to export")
+	from := fs.String("from", "", "First offset exported, or a time ago like 1day, from the oldest if empty")
+	to := fs.String("to", "", "Last offset exported, or a time ago like 1day, until the latest if empty")
+	out := fs.String("o", "-", "File where the export is written, stdout if -")
+	_ = fs.Parse(args)
+
+	nl, err := netlog.NewNetLog(*dir)
+	fatalOn(err)
+
+	t, err := nl.Topic(*topic)
+	fatalOn(err)
+
+	first, err := t.ParseOffset(*from)
+	fatalOn(err)
+
+	last := int64(-1)
+	if *to != "" {
+		last, err = t.ParseOffset(*to)
+		fatalOn(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		fatalOn(err)
+		defer func() { fatalOn(f.Close()) }()
+		w = f
+	}
+
+	fatalOn(t.Export(w, first, last))
+	log.Printf("info: exported topic %q", t.Name())
+}
+
+// runImport implements `netlog import`, creating a topic from an export read from a file or stdin.
+// The server must not be running on the same data folder, use the HTTP endpoint instead.
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := fs.String("dir", "./data", "Data folder")
+	topic := fs.String("topic", "", "Name of the imported topic, the exported name if empty")
+	preserve := fs.Bool("preserve_offsets", false, "Keep the exported offsets instead of starting from 0")
+	_ = fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if file := fs.Arg(0); file != "" && file != "-" {
+		f, err := os.Open(file)
+		fatalOn(err)
+		defer logClose(f)
+		r = f
+	}
+
+	nl, err := netlog.NewNetLog(*dir)
+	fatalOn(err)
+
+	_, err = nl.ImportTopic(*topic, r, *preserve)
+	fatalOn(err)
+}
+
+func logClose(c io.Closer) {
+	if err := c.Close(); err != nil {
+		log.Printf("error: %s", err)
+	}
+}
@@ -5,33 +5,72 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"comail.io/go/colog"
 	"github.com/ninibe/bigduration"
 	"github.com/ninibe/netlog"
+	"github.com/ninibe/netlog/biglog"
 	"github.com/ninibe/netlog/transport"
+	nlgrpc "github.com/ninibe/netlog/transport/grpc"
 	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
 )
 
 var (
 	debug         = flag.Bool("debug", false, "Start on debug mode")
 	listen        = flag.String("listen", ":7200", "Listen address")
+	grpcListen    = flag.String("grpc_listen", "", "Listen address for the gRPC interface, disabled if empty")
+	kinListen     = flag.String("kinesis_listen", "", "Listen address for the Kinesis compatible interface, disabled if empty")
+	follow        = flag.String("follow", "", "URL of a leader to replicate, starts as read-only follower if set")
+	followToken   = flag.String("follow_token", "", "Bearer token to authenticate to the leader")
+	authConfig    = flag.String("auth_config", "", "JSON file of tokens, HMAC keys and ACLs of the HTTP and Kinesis interfaces, no authentication if empty")
+	followIntv    = flag.String("follow_interval", "1s", "Interval at which the leader is checked for new topics and lag")
 	dataDir       = flag.String("dir", "./data", "Data folder")
 	logLevel      = flag.String("loglevel", "info", "Logging level")
 	monInterval   = flag.String("monitor_interval", "10s", "Interval for segment size and age checks")
+	shutdownTime  = flag.String("shutdown_timeout", "30s", "Time given to requests in progress and readers to finish when shutting down")
+	scannerTTL    = flag.String("scanner_ttl", "0s", "Idle time after which non-persistent scanners are removed, never if zero")
 	segAge        = flag.String("segment_age", "30day", "Time since the last write in a segment until it gets discarded")
 	segSize       = flag.Int64("segment_size", 1024*1024*1024, "Maximum topic segment size in bytes")
 	batchNum      = flag.Int("batch_num_messages", 100, "Default maximum number of messages to be batched")
 	batchInterval = flag.String("batch_interval", "200ms", "Default interval at which batched messages are flushed to disk.")
 	compression   = flag.Int("compression", 1, "Default compression for batches: 1 = gzip, 2 = snappy, 3 = none")
+	durability    = flag.String("durability", "buffered", "Default acknowledgement of writes: buffered, flushed or synced")
+	maxTopicBytes = flag.Int64("max_topic_bytes", 0, "Default maximum disk size of a topic in bytes, unlimited if 0")
+	quotaPolicy   = flag.String("quota_policy", "trim", "What happens over the size limits: trim old segments or reject writes")
+	dataQuota     = flag.Int64("data_quota", 0, "Maximum disk size of all topics together in bytes, unlimited if 0")
+	archiveDir    = flag.String("archive_dir", "", "Folder where discarded segments are archived, disabled if empty")
+	s3Endpoint    = flag.String("archive_s3_endpoint", "", "URL of an S3 compatible store where discarded segments are archived, disabled if empty")
+	s3Bucket      = flag.String("archive_s3_bucket", "", "Bucket of the S3 archive, credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	s3Region      = flag.String("archive_s3_region", "us-east-1", "Region of the S3 archive bucket")
 )
 
 func main() {
-	flag.Parse()
 	colog.Register()
+	colog.SetMinLevel(colog.LInfo)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "repair":
+			runRepair(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Parse()
 
 	ll, err := colog.ParseLevel(*logLevel)
 	fatalOn(err)
@@ -42,6 +81,18 @@ func main() {
 		colog.SetMinLevel(colog.LTrace)
 	}
 
+	var htOpts []transport.HTTPOption
+	if *authConfig != "" {
+		if *grpcListen != "" {
+			log.Fatalf("alert: the gRPC interface is not authenticated, it can't be enabled with -auth_config\n")
+		}
+
+		config, err := transport.ReadAuthConfig(*authConfig)
+		fatalOn(err)
+		htOpts = append(htOpts, transport.Auth(config.Authenticator(), config.ACL))
+		log.Printf("info: authenticating HTTP and Kinesis requests with %q", *authConfig)
+	}
+
 	var server http.Server
 	server.Addr = *listen
 	err = http2.ConfigureServer(&server, nil)
@@ -51,27 +102,177 @@ func main() {
 	fatalOn(err)
 	mIterval, err := bigduration.ParseBigDuration(*monInterval)
 	fatalOn(err)
+	sTTL, err := bigduration.ParseBigDuration(*scannerTTL)
+	fatalOn(err)
+	sTimeout, err := bigduration.ParseBigDuration(*shutdownTime)
+	fatalOn(err)
 
 	bInterval, err := bigduration.ParseBigDuration(*batchInterval)
 	fatalOn(err)
 
+	dur, err := netlog.ParseDurability(*durability)
+	fatalOn(err)
+
+	qPolicy, err := netlog.ParseQuotaPolicy(*quotaPolicy)
+	fatalOn(err)
+
 	topSettings := netlog.TopicSettings{
 		SegAge:           segAge,
 		SegSize:          *segSize,
 		BatchNumMessages: *batchNum,
 		BatchInterval:    bInterval,
 		CompressionType:  netlog.CompressionType(*compression),
+		Durability:       dur,
+		MaxBytes:         *maxTopicBytes,
+		QuotaPolicy:      qPolicy,
 	}
 
-	nl, err := netlog.NewNetLog(*dataDir,
+	opts := []netlog.Option{
 		netlog.DefaultTopicSettings(topSettings),
-		netlog.MonitorInterval(mIterval))
+		netlog.MonitorInterval(mIterval),
+		netlog.ScannerTTL(sTTL),
+		netlog.DataQuota(*dataQuota, qPolicy),
+	}
+
+	if archive := newArchive(); archive != nil {
+		opts = append(opts, netlog.ArchiveSegments(archive))
+	}
+
+	nl, err := netlog.NewNetLog(*dataDir, opts...)
 	fatalOn(err)
 
-	http.Handle("/", transport.NewHTTPTransport(nl))
+	ht := transport.NewHTTPTransport(nl, htOpts...)
+
+	var grpcServer *grpc.Server
+	if *grpcListen != "" {
+		grpcServer = serveGRPC(nl, *grpcListen)
+	}
+
+	// Kinesis requests can create and delete any stream
+	var kinServer *http.Server
+	if *kinListen != "" {
+		kinServer = serveKinesis(ht.Protect(transport.NewKinesisTransport(nl), transport.RightAdmin), *kinListen)
+	}
+
+	fctx, stopFollower := context.WithCancel(context.Background())
+	defer stopFollower()
+	if *follow != "" {
+		fInterval, err := bigduration.ParseBigDuration(*followIntv)
+		fatalOn(err)
+
+		f := transport.NewFollower(nl, *follow, fInterval.Duration())
+		f.UseToken(*followToken)
+		http.Handle("/_replication", ht.Protect(f, transport.RightAdmin))
+		log.Printf("info: following leader %q", *follow)
+		go func() { _ = f.Run(fctx) }()
+	}
+
+	http.Handle("/metrics", ht.Protect(ht.MetricsHandler(), transport.RightConsume))
+	http.Handle("/", ht)
 	log.Printf("info: listening on %q", server.Addr)
 	log.Printf("info: data dir on %q", *dataDir)
-	log.Fatalf("alert: %s\n", server.ListenAndServe())
+
+	served := make(chan error, 1)
+	go func() { served <- server.ListenAndServe() }()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err = <-served:
+		log.Fatalf("alert: %s\n", err)
+	case s := <-sig:
+		log.Printf("info: received %s, shutting down", s)
+	}
+
+	// a second signal stops right away
+	signal.Stop(sig)
+	stopFollower()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sTimeout.Duration())
+	defer cancel()
+	if err = server.Shutdown(ctx); err != nil {
+		log.Printf("warn: requests still in progress: %s", err)
+	}
+
+	if kinServer != nil {
+		if err = kinServer.Shutdown(ctx); err != nil {
+			log.Printf("warn: Kinesis requests still in progress: %s", err)
+		}
+	}
+
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
+
+	fatalOn(nl.Close(ctx))
+	log.Printf("info: shut down")
+}
+
+func newArchive() biglog.Archive {
+	if *s3Endpoint != "" {
+		log.Printf("info: archiving segments on %q bucket %q", *s3Endpoint, *s3Bucket)
+		return &biglog.S3Archive{
+			Endpoint:  *s3Endpoint,
+			Bucket:    *s3Bucket,
+			Region:    *s3Region,
+			AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		}
+	}
+
+	if *archiveDir != "" {
+		archive, err := biglog.NewDirArchive(*archiveDir)
+		fatalOn(err)
+		log.Printf("info: archiving segments on %q", *archiveDir)
+		return archive
+	}
+
+	return nil
+}
+
+func serveGRPC(nl *netlog.NetLog, addr string) *grpc.Server {
+	lis, err := net.Listen("tcp", addr)
+	fatalOn(err)
+
+	server := grpc.NewServer()
+	nlgrpc.NewTransport(nl).Register(server)
+	log.Printf("info: gRPC listening on %q", addr)
+	go func() {
+		// Serve returns nil once stopped
+		if err := server.Serve(lis); err != nil {
+			log.Fatalf("alert: %s\n", err)
+		}
+	}()
+
+	return server
+}
+
+// stopGRPC waits for the gRPC calls in progress until ctx is done, then cancels them.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Printf("warn: gRPC calls still in progress: %s", ctx.Err())
+		server.Stop()
+	}
+}
+
+func serveKinesis(h http.Handler, addr string) *http.Server {
+	server := &http.Server{Addr: addr, Handler: h}
+	log.Printf("info: Kinesis API listening on %q", addr)
+	go func() {
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatalf("alert: %s\n", err)
+		}
+	}()
+
+	return server
 }
 
 func fatalOn(err error) {
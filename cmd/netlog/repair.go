@@ -0,0 +1,43 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/ninibe/netlog"
+	"github.com/ninibe/netlog/biglog"
+)
+
+// runRepair implements `netlog repair`, rebuilding the indexes of a topic and truncating corrupted
+// data, printing a report per segment. The topic is opened without loading its scanners and groups
+// so that no segment is busy. The server must not be running on the same data folder, use the HTTP
+// endpoint instead.
+func runRepair(args []string) {
+	fs := flag.NewFlagSet("repair", flag.ExitOnError)
+	dir := fs.String("dir", "./data", "Data folder")
+	topic := fs.String("topic", "", "Topic to repair")
+	quarantine := fs.Bool("quarantine", false, "Move corrupted data to the quarantine folder of the topic, keeping the valid data following it")
+	_ = fs.Parse(args)
+
+	if *topic == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	bl, err := biglog.Open(filepath.Join(*dir, *topic))
+	fatalOn(err)
+	defer logClose(bl)
+
+	reports, err := netlog.RepairLog(bl, *quarantine)
+	fatalOn(err)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	fatalOn(enc.Encode(reports))
+}
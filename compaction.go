@@ -0,0 +1,242 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// CleanupPolicy defines how a topic discards old data.
+type CleanupPolicy string
+
+const (
+	// CleanupDelete discards whole segments older than the segment age.
+	CleanupDelete CleanupPolicy = "delete"
+	// CleanupCompact keeps only the latest message of every key.
+	CleanupCompact CleanupPolicy = "compact"
+)
+
+// DefaultTombstoneRetention is how long tombstones are kept if no retention is given.
+const DefaultTombstoneRetention = 24 * time.Hour
+
+// compactionInterval is the minimum time between compactions of a topic.
+var compactionInterval = time.Minute
+
+// errNothingToCompact aborts the rewrite of segments without messages to discard.
+var errNothingToCompact = errors.New("netlog: nothing to compact")
+
+// ParseCleanupPolicy parses a cleanup policy, empty strings
+// are valid and mean the default policy of the server.
+func ParseCleanupPolicy(s string) (CleanupPolicy, error) {
+	p := CleanupPolicy(s)
+	switch p {
+	case "", CleanupDelete, CleanupCompact:
+		return p, nil
+	}
+
+	return "", ErrInvalidCleanupPolicy
+}
+
+type compactor struct {
+	mu      sync.Mutex
+	running bool
+	last    time.Time
+}
+
+// checkCompaction starts a compaction in the background unless one
+// is running or the last one ran within the compaction interval.
+func (t *Topic) checkCompaction() {
+	t.compactor.mu.Lock()
+	defer t.compactor.mu.Unlock()
+
+	if t.compactor.running || time.Since(t.compactor.last) < compactionInterval {
+		return
+	}
+
+	t.compactor.running = true
+	go func() {
+		err := t.Compact()
+		if err != nil {
+			log.Printf("error: failed to compact %q: %s", t.name, err)
+		}
+
+		t.compactor.mu.Lock()
+		t.compactor.running = false
+		t.compactor.mu.Unlock()
+	}()
+}
+
+// Compact rewrites the closed segments of the topic keeping only the latest message
+// of every key. Messages keep their offsets, the offsets of discarded ones are skipped
+// by scanners and reading them returns ErrOffsetNotFound. Tombstones, messages with a
+// key and an empty payload, are discarded once older than the tombstone retention.
+// Messages without a key are never discarded. Segments being read are skipped.
+func (t *Topic) Compact() error {
+	t.compactor.mu.Lock()
+	t.compactor.last = time.Now()
+	t.compactor.mu.Unlock()
+
+	latest, err := t.latestKeys()
+	if err != nil {
+		return err
+	}
+
+	info, err := t.bl.Info()
+	if err != nil {
+		return err
+	}
+
+	settings := t.Settings()
+	retention := settings.TombstoneRetention.Duration()
+	if retention == 0 {
+		retention = DefaultTombstoneRetention
+	}
+
+	// the last segment is still being written
+	for _, seg := range info.Segments[:len(info.Segments)-1] {
+		err = t.bl.RewriteSegment(seg.FirstOffset, func(w *biglog.SegmentWriter) error {
+			return t.compactSegment(w, latest, retention)
+		})
+
+		switch err {
+		case nil, errNothingToCompact:
+		case biglog.ErrSegmentBusy:
+			log.Printf("warn: skipping compaction of busy segment %d on %q", seg.FirstOffset, t.name)
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// latestKeys returns the offset of the latest message of every key in the topic.
+func (t *Topic) latestKeys() (map[string]int64, error) {
+	end := t.bl.Latest()
+	sc, err := biglog.NewScanner(t.bl, t.bl.Oldest())
+	if err != nil {
+		return nil, err
+	}
+
+	defer logClose(sc)
+
+	latest := make(map[string]int64)
+	for sc.Scan() && sc.Offset() <= end {
+		// offsets already compacted
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		msgs, err := entryMessages(sc.Bytes(), sc.ODelta())
+		if err != nil {
+			log.Printf("warn: skipping unreadable entry at %d on %q: %s", sc.Offset(), t.name, err)
+			continue
+		}
+
+		for k, m := range msgs {
+			if key := m.Key(); key != nil {
+				latest[string(key)] = sc.Offset() + int64(k)
+			}
+		}
+	}
+
+	return latest, sc.Err()
+}
+
+// compactSegment writes the messages to keep from the segment being rewritten.
+func (t *Topic) compactSegment(w *biglog.SegmentWriter, latest map[string]int64, retention time.Duration) error {
+	first, next := w.Segment()
+	sc, err := biglog.NewScanner(t.bl, first)
+	if err != nil {
+		return err
+	}
+
+	defer logClose(sc)
+
+	now := time.Now()
+	var discarded int
+	for sc.Scan() && sc.Offset() < next {
+		offset, n := sc.Offset(), sc.ODelta()
+		entry := Message(sc.Bytes())
+		if len(entry) == 0 {
+			continue
+		}
+
+		msgs, err := entryMessages(entry, n)
+		if err != nil || len(msgs) != n {
+			log.Printf("warn: keeping unreadable entry at %d on %q", offset, t.name)
+			msgs = nil
+		}
+
+		keep := make([]bool, len(msgs))
+		kept := 0
+		for k, m := range msgs {
+			keep[k] = keepCompacted(m, offset+int64(k), latest, now.Add(-retention))
+			if keep[k] {
+				kept++
+			}
+		}
+
+		// copy untouched entries as they are
+		if kept == len(msgs) {
+			if err = w.WriteN(offset, entry, n); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		discarded += n - kept
+		for k, m := range msgs {
+			if !keep[k] {
+				continue
+			}
+
+			if err = w.WriteN(offset+int64(k), m, 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if sc.Err() != nil {
+		return sc.Err()
+	}
+
+	if discarded == 0 {
+		return errNothingToCompact
+	}
+
+	log.Printf("info: compacted %d messages from segment %d on %q", discarded, first, t.name)
+	return nil
+}
+
+// keepCompacted returns whether the message at offset survives compaction given the
+// latest offset of every key, tombstones survive only if written after `expired`.
+func keepCompacted(m Message, offset int64, latest map[string]int64, expired time.Time) bool {
+	meta, err := m.Meta()
+	if err != nil || meta.Key == nil {
+		return true
+	}
+
+	if latest[string(meta.Key)] > offset {
+		return false
+	}
+
+	return len(m.Payload()) > 0 || meta.Timestamp.After(expired)
+}
+
+// entryMessages returns the messages of a stored entry holding n offsets.
+func entryMessages(entry Message, n int) ([]Message, error) {
+	if n == 1 {
+		return []Message{entry}, nil
+	}
+
+	return Unpack(entry)
+}
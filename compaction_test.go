@@ -0,0 +1,109 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		CleanupPolicy:   CleanupCompact,
+		CompressionType: CompressionGzip,
+	})
+	panicOn(err)
+
+	if _, err = nl.CreateTopic(randStr(6), TopicSettings{CleanupPolicy: "forever"}); err != ErrInvalidCleanupPolicy {
+		t.Errorf("Expected ErrInvalidCleanupPolicy, got %v", err)
+	}
+
+	now := time.Now()
+	keyed := func(key, payload string, ts time.Time) Message {
+		return MessageWithMeta([]byte(payload), MessageMeta{Key: []byte(key), Timestamp: ts})
+	}
+
+	write := func(msgs ...Message) {
+		_, err2 := topic.WriteMessages(msgs)
+		panicOn(err2)
+	}
+
+	write(keyed("k1", "v1", now))                         // 0
+	write(keyed("k2", "v1", now))                         // 1
+	write(MessageFromPayload([]byte("no key")))           // 2
+	write(keyed("k1", "v2", now))                         // 3
+	write(keyed("k2", "", now.Add(-48*time.Hour)))        // 4 expired tombstone
+	write(keyed("ka", "v1", now), keyed("k1", "v3", now), // 5, 6
+		keyed("kb", "v1", now), keyed("kc", "", now)) // 7, 8 recent tombstone
+	panicOn(topic.bl.Split())
+	write(keyed("kb", "v2", now)) // 9
+
+	panicOn(topic.Compact())
+
+	// nothing left to discard
+	panicOn(topic.Compact())
+
+	check := func(topic *Topic) {
+		ts, err2 := topic.NewScanner(0, false)
+		panicOn(err2)
+		defer logClose(ts)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var offsets []int64
+		for {
+			_, o, err3 := ts.Scan(ctx)
+			if err3 != nil {
+				break
+			}
+			offsets = append(offsets, o)
+		}
+
+		if exp := []int64{2, 5, 6, 8, 9}; !reflect.DeepEqual(offsets, exp) {
+			t.Errorf("Scanned offsets %v. Expected %v", offsets, exp)
+		}
+
+		if _, err2 = topic.Message(3); err2 != ErrOffsetNotFound {
+			t.Errorf("Expected ErrOffsetNotFound, got %v", err2)
+		}
+
+		m, err2 := topic.Message(6)
+		panicOn(err2)
+		if string(m.Key()) != "k1" || string(m.Payload()) != "v3" {
+			t.Errorf("Unexpected message at offset 6: %s %s", m.Key(), m.Payload())
+		}
+
+		// starting within compacted offsets
+		ts2, err2 := topic.NewScanner(3, false)
+		panicOn(err2)
+		defer logClose(ts2)
+		if _, o, err3 := ts2.Scan(ctx); err3 != nil || o != 5 {
+			t.Errorf("Scanned offset %d from 3. Expected 5: %v", o, err3)
+		}
+
+		iErrs, err2 := topic.CheckIntegrity(ctx, 0)
+		panicOn(err2)
+		if len(iErrs) > 0 {
+			t.Errorf("Integrity errors in compacted topic %+v", iErrs[0])
+		}
+	}
+
+	check(topic)
+
+	nl2, err := NewNetLog(nl.dataDir)
+	panicOn(err)
+	topic2, err := nl2.Topic(topicName)
+	panicOn(err)
+	check(topic2)
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
@@ -0,0 +1,454 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/comail/go-uuid/uuid"
+)
+
+// file extension for persisted consumer groups
+const groupExt = ".group"
+
+// groupSessionTimeout is the time after which a silent member is removed from its group.
+const groupSessionTimeout = time.Minute
+
+var groupNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]{1,128}$`)
+
+// ConsumerGroup shares the consumption of a topic among a set of members.
+// Every fetch claims a range of offsets for a member that no other member
+// receives unless it's released when the member leaves or times out.
+// Offsets are committed explicitly by the members and the committed offset
+// of the group survives server restarts. ConsumerGroups are thread-safe.
+type ConsumerGroup struct {
+	name    string
+	topic   *Topic
+	f       *os.File
+	fpath   string
+	timeout time.Duration
+
+	mu        sync.Mutex
+	next      int64 // next offset never claimed
+	committed int64 // every offset before it is committed
+	members   map[string]*groupMember
+	released  []offsetRange
+
+	fetchMu sync.Mutex
+	sc      *BLTopicScanner
+}
+
+// offsetRange is the range of offsets [From, To).
+type offsetRange struct {
+	From int64
+	To   int64
+}
+
+type groupMember struct {
+	claims   []offsetRange
+	lastSeen time.Time
+	fetching int
+}
+
+// GroupInfo holds the state of a consumer group.
+type GroupInfo struct {
+	Name      string                     `json:"name"`
+	Committed int64                      `json:"committed"`
+	Next      int64                      `json:"next"`
+	Members   map[string]GroupMemberInfo `json:"members"`
+}
+
+// GroupMemberInfo holds the state of a member of a consumer group.
+type GroupMemberInfo struct {
+	// Claimed is the number of offsets fetched by the member and not yet committed.
+	Claimed  int64     `json:"claimed"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// newConsumerGroup returns a consumer group with every offset before `from` committed.
+func newConsumerGroup(t *Topic, name string, from int64) (*ConsumerGroup, error) {
+	if !groupNameRegexp.MatchString(name) {
+		return nil, ErrInvalidGroupName
+	}
+
+	if from < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	err := os.MkdirAll(t.readersDir(), 0755)
+	if err != nil {
+		log.Printf("error: %s", err)
+		return nil, ErrInvalidDir
+	}
+
+	sc, err := newBLTopicScanner(t, name, from)
+	if err != nil {
+		return nil, ExtErr(err)
+	}
+
+	fpath := t.groupPath(name)
+	f, err := os.OpenFile(fpath, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		log.Printf("error: %s", err)
+		logClose(sc)
+		return nil, ErrInvalidDir
+	}
+
+	g := &ConsumerGroup{
+		name:      name,
+		topic:     t,
+		f:         f,
+		fpath:     fpath,
+		timeout:   groupSessionTimeout,
+		next:      from,
+		committed: from,
+		members:   make(map[string]*groupMember),
+		sc:        sc,
+	}
+
+	return g, g.persist()
+}
+
+// Name returns the name of the group.
+func (g *ConsumerGroup) Name() string {
+	return g.name
+}
+
+// Join adds a new member to the group and returns its ID.
+func (g *ConsumerGroup) Join() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.expire()
+	ID := uuid.New()
+	g.members[ID] = &groupMember{lastSeen: time.Now()}
+
+	log.Printf("info: member %s joined group %q on %q", ID, g.name, g.topic.Name())
+	return ID
+}
+
+// Leave removes a member from the group, offsets
+// claimed and not committed by it are handed out again.
+func (g *ConsumerGroup) Leave(member string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m, err := g.member(member)
+	if err != nil {
+		return err
+	}
+
+	g.release(member, m)
+	log.Printf("info: member %s left group %q on %q", member, g.name, g.topic.Name())
+	return nil
+}
+
+// Fetch claims a range of up to max offsets for a member and returns its messages
+// along with their offsets, which are consecutive unless the topic is compacted.
+// Offsets released by other members are handed out first. Fetch blocks until there
+// is data available or the context is done, in which case ErrEndOfTopic is returned.
+func (g *ConsumerGroup) Fetch(ctx context.Context, member string, max int) (msgs []Message, offsets []int64, err error) {
+	if max < 1 {
+		return nil, nil, ErrBadRequest
+	}
+
+	g.mu.Lock()
+	m, err := g.member(member)
+	if err != nil {
+		g.mu.Unlock()
+		return nil, nil, err
+	}
+
+	for {
+		claim, ok := g.claimReleased(int64(max))
+		if !ok {
+			break
+		}
+
+		m.claims = append(m.claims, claim)
+		g.mu.Unlock()
+
+		msgs, offsets, err = g.read(claim)
+		if err != nil || len(msgs) > 0 {
+			return msgs, offsets, err
+		}
+
+		// every offset of the range was removed by compaction
+		g.mu.Lock()
+		if _, ok = g.members[member]; !ok {
+			g.mu.Unlock()
+			return nil, nil, ErrMemberNotFound
+		}
+
+		if err = g.drop(m, claim); err != nil {
+			g.mu.Unlock()
+			return nil, nil, err
+		}
+	}
+
+	m.fetching++
+	g.mu.Unlock()
+
+	msgs, offsets, err = g.scan(ctx, max)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m.fetching--
+	m.lastSeen = time.Now()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claim := offsetRange{From: offsets[0], To: offsets[len(offsets)-1] + 1}
+	g.next = claim.To
+
+	// left the group while waiting
+	if _, ok := g.members[member]; !ok {
+		g.release(member, &groupMember{claims: []offsetRange{claim}})
+		return nil, nil, ErrMemberNotFound
+	}
+
+	m.claims = append(m.claims, claim)
+	return msgs, offsets, nil
+}
+
+// scan reads new messages blocking only for the first one.
+func (g *ConsumerGroup) scan(ctx context.Context, max int) (msgs []Message, offsets []int64, err error) {
+	g.fetchMu.Lock()
+	defer g.fetchMu.Unlock()
+
+	return g.sc.ScanN(ctx, max, 0)
+}
+
+// claimReleased takes up to n offsets from the released ranges, g.mu must be held.
+func (g *ConsumerGroup) claimReleased(n int64) (claim offsetRange, ok bool) {
+	oldest := g.topic.bl.Earliest()
+	for len(g.released) > 0 {
+		r := &g.released[0]
+		// discarded by the retention policy
+		if r.From < oldest {
+			r.From = oldest
+		}
+
+		if r.From >= r.To {
+			g.released = g.released[1:]
+			continue
+		}
+
+		claim = offsetRange{From: r.From, To: r.From + n}
+		if claim.To >= r.To {
+			claim.To = r.To
+			g.released = g.released[1:]
+		} else {
+			r.From = claim.To
+		}
+
+		return claim, true
+	}
+
+	return claim, false
+}
+
+// drop removes a claim with nothing to process from a member, g.mu must be held.
+func (g *ConsumerGroup) drop(m *groupMember, claim offsetRange) error {
+	for k, c := range m.claims {
+		if c == claim {
+			m.claims = append(m.claims[:k:k], m.claims[k+1:]...)
+			break
+		}
+	}
+
+	return g.advance()
+}
+
+// read returns the messages of a previously released range,
+// none if compaction removed every offset of it.
+func (g *ConsumerGroup) read(r offsetRange) (msgs []Message, offsets []int64, err error) {
+	sc, err := newBLTopicScanner(g.topic, g.name, r.From)
+	if err != nil {
+		return nil, nil, ExtErr(err)
+	}
+
+	defer logClose(sc)
+
+	// the range was already written, never wait
+	for {
+		batch, bOffsets, err := sc.ScanN(doneCtx, int(r.To-r.From), 0)
+		if err == ErrEndOfTopic {
+			return msgs, offsets, nil
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for k, o := range bOffsets {
+			if o >= r.To {
+				return msgs, offsets, nil
+			}
+
+			msgs = append(msgs, batch[k])
+			offsets = append(offsets, o)
+		}
+	}
+}
+
+// Commit marks every offset up to `offset` fetched by the member as processed.
+// The committed offset of the group moves forward once there are no pending
+// offsets before it. ErrInvalidOffset is returned if the offset was never fetched.
+func (g *ConsumerGroup) Commit(member string, offset int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m, err := g.member(member)
+	if err != nil {
+		return err
+	}
+
+	if offset < 0 || offset >= g.next {
+		return ErrInvalidOffset
+	}
+
+	claims := m.claims[:0]
+	for _, c := range m.claims {
+		if c.From <= offset {
+			c.From = offset + 1
+		}
+
+		if c.From < c.To {
+			claims = append(claims, c)
+		}
+	}
+
+	m.claims = claims
+	return g.advance()
+}
+
+// advance moves the committed offset forward up to the first offset pending, g.mu must be held.
+func (g *ConsumerGroup) advance() error {
+	committed := g.committedOffset()
+	if committed == g.committed {
+		return nil
+	}
+
+	g.committed = committed
+	return g.persist()
+}
+
+// committedOffset returns the first offset not yet committed, g.mu must be held.
+func (g *ConsumerGroup) committedOffset() int64 {
+	committed := g.next
+	for _, r := range g.released {
+		if r.From < committed {
+			committed = r.From
+		}
+	}
+
+	for _, m := range g.members {
+		for _, r := range m.claims {
+			if r.From < committed {
+				committed = r.From
+			}
+		}
+	}
+
+	return committed
+}
+
+// Info returns the state of the group and its members.
+func (g *ConsumerGroup) Info() GroupInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.expire()
+	info := GroupInfo{
+		Name:      g.name,
+		Committed: g.committed,
+		Next:      g.next,
+		Members:   make(map[string]GroupMemberInfo, len(g.members)),
+	}
+
+	for ID, m := range g.members {
+		var claimed int64
+		for _, r := range m.claims {
+			claimed += r.To - r.From
+		}
+
+		info.Members[ID] = GroupMemberInfo{
+			Claimed:  claimed,
+			LastSeen: m.lastSeen,
+		}
+	}
+
+	return info
+}
+
+// Close implements io.Closer and releases the group resources, the committed offset is kept.
+func (g *ConsumerGroup) Close() error {
+	g.fetchMu.Lock()
+	defer g.fetchMu.Unlock()
+
+	if err := g.sc.Close(); err != nil {
+		return err
+	}
+
+	return g.f.Close()
+}
+
+// member returns an active member refreshing its
+// last seen time, g.mu must be held.
+func (g *ConsumerGroup) member(ID string) (*groupMember, error) {
+	g.expire()
+	m, ok := g.members[ID]
+	if !ok {
+		return nil, ErrMemberNotFound
+	}
+
+	m.lastSeen = time.Now()
+	return m, nil
+}
+
+// expire removes the members that timed out, g.mu must be held.
+func (g *ConsumerGroup) expire() {
+	for ID, m := range g.members {
+		if m.fetching == 0 && time.Since(m.lastSeen) > g.timeout {
+			log.Printf("info: member %s of group %q on %q timed out", ID, g.name, g.topic.Name())
+			g.release(ID, m)
+		}
+	}
+}
+
+// release hands out the pending claims of a member and removes it, g.mu must be held.
+func (g *ConsumerGroup) release(ID string, m *groupMember) {
+	g.released = append(g.released, m.claims...)
+	sort.Slice(g.released, func(i, j int) bool {
+		return g.released[i].From < g.released[j].From
+	})
+
+	delete(g.members, ID)
+}
+
+func (g *ConsumerGroup) persist() error {
+	buf := make([]byte, 8)
+	enc.PutUint64(buf, uint64(g.committed))
+	_, err := g.f.WriteAt(buf, 0)
+	if err != nil {
+		log.Printf("error: failed to persist group %q on %q: %s", g.name, g.topic.Name(), err)
+	}
+
+	return err
+}
+
+func (t *Topic) groupPath(name string) string {
+	return filepath.Join(t.readersDir(), name+groupExt)
+}
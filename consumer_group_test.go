@@ -0,0 +1,200 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConsumerGroup(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	messages := randMessageSet()
+	_, err = topic.WriteMessages(messages[:10])
+	panicOn(err)
+
+	g, err := topic.NewConsumerGroup("workers", 0)
+	panicOn(err)
+
+	if _, err = topic.NewConsumerGroup("workers", 0); err != ErrGroupExists {
+		t.Errorf("Expected ErrGroupExists, got %v", err)
+	}
+
+	if _, err = topic.NewConsumerGroup("../workers", 0); err != ErrInvalidGroupName {
+		t.Errorf("Expected ErrInvalidGroupName, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	m1, m2 := g.Join(), g.Join()
+	fetch := func(member string, max int, expOffset int64, expLen int) {
+		msgs, offsets, err2 := g.Fetch(ctx, member, max)
+		panicOn(err2)
+		offset := offsets[0]
+		if offset != expOffset || len(msgs) != expLen {
+			t.Fatalf("Fetched %d messages from %d. Expected %d from %d", len(msgs), offset, expLen, expOffset)
+		}
+
+		for k, m := range msgs {
+			if !bytes.Equal(m, messages[offset+int64(k)]) {
+				t.Errorf("Message %d not equal to original", offset+int64(k))
+			}
+		}
+	}
+
+	// ranges are handed out without duplication
+	fetch(m1, 3, 0, 3)
+	fetch(m2, 3, 3, 3)
+
+	// out of order commits do not move the group forward
+	panicOn(g.Commit(m2, 5))
+	if info := g.Info(); info.Committed != 0 || info.Next != 6 {
+		t.Errorf("Unexpected group state %+v", info)
+	}
+
+	panicOn(g.Commit(m1, 1))
+	if info := g.Info(); info.Committed != 2 || info.Members[m1].Claimed != 1 {
+		t.Errorf("Unexpected group state %+v", info)
+	}
+
+	if err = g.Commit(m1, 6); err != ErrInvalidOffset {
+		t.Errorf("Expected ErrInvalidOffset, got %v", err)
+	}
+
+	// pending offsets of leaving members are handed out again
+	panicOn(g.Leave(m1))
+	fetch(m2, 3, 2, 1)
+	fetch(m2, 10, 6, 4)
+	panicOn(g.Commit(m2, 9))
+
+	if info := g.Info(); info.Committed != 10 {
+		t.Errorf("Unexpected group state %+v", info)
+	}
+
+	// nothing left
+	cctx, ccancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer ccancel()
+	if _, _, err = g.Fetch(cctx, m2, 1); err != ErrEndOfTopic {
+		t.Errorf("Expected ErrEndOfTopic, got %v", err)
+	}
+
+	if _, _, err = g.Fetch(ctx, m1, 1); err != ErrMemberNotFound {
+		t.Errorf("Expected ErrMemberNotFound, got %v", err)
+	}
+
+	// committed offset survives restarts
+	nl2, err := NewNetLog(nl.dataDir)
+	panicOn(err)
+	topic2, err := nl2.Topic(topicName)
+	panicOn(err)
+	g2, err := topic2.ConsumerGroup("workers")
+	panicOn(err)
+	if info := g2.Info(); info.Committed != 10 || info.Next != 10 {
+		t.Errorf("Group not restored %+v", info)
+	}
+
+	panicOn(topic2.DeleteConsumerGroup("workers"))
+	panicOn(g.Close())
+	panicOn(nl.DeleteTopic(topicName, true))
+}
+
+func TestConsumerGroupTimeout(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	_, err = topic.WriteMessages(randMessageSet()[:4])
+	panicOn(err)
+
+	g, err := topic.NewConsumerGroup("workers", 0)
+	panicOn(err)
+	g.timeout = 10 * time.Millisecond
+
+	m1 := g.Join()
+	_, _, err = g.Fetch(context.Background(), m1, 2)
+	panicOn(err)
+
+	time.Sleep(20 * time.Millisecond)
+	m2 := g.Join()
+	_, offsets, err := g.Fetch(context.Background(), m2, 2)
+	panicOn(err)
+	if offsets[0] != 0 {
+		t.Errorf("Offsets of timed out member not released, fetched %d", offsets[0])
+	}
+
+	if err = g.Commit(m1, 0); err != ErrMemberNotFound {
+		t.Errorf("Expected ErrMemberNotFound, got %v", err)
+	}
+}
+
+func TestConsumerGroupCompactedRelease(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{CleanupPolicy: CleanupCompact})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	write := func(key string) {
+		_, err2 := topic.WriteMessage(MessageWithMeta(randData(10), MessageMeta{Key: []byte(key)}))
+		panicOn(err2)
+	}
+
+	write("k1")
+	write("k2")
+	panicOn(topic.bl.Split())
+	write("k1")
+	write("k2")
+	panicOn(topic.bl.Split())
+
+	g, err := topic.NewConsumerGroup("workers", 0)
+	panicOn(err)
+
+	m0, m1 := g.Join(), g.Join()
+	_, _, err = g.Fetch(context.Background(), m1, 2)
+	panicOn(err)
+	_, _, err = g.Fetch(context.Background(), m0, 2)
+	panicOn(err)
+	panicOn(g.Leave(m1))
+
+	// the released range is removed by compaction
+	panicOn(topic.Compact())
+	write("k3")
+
+	m2 := g.Join()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, offsets, err := g.Fetch(ctx, m2, 2)
+	panicOn(err)
+	if len(offsets) != 1 || offsets[0] != 4 {
+		t.Errorf("Unexpected offsets fetched after a compacted range %v", offsets)
+	}
+
+	if info := g.Info(); info.Committed != 2 {
+		t.Errorf("Compacted range not committed, committed offset %d", info.Committed)
+	}
+}
@@ -0,0 +1,80 @@
+// generated file - DO NOT EDIT
+// command: atomicmapper -pointer -type ConsumerGroup
+
+package netlog
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConsumerGroupAtomicMap is a copy-on-write thread-safe map of pointers to ConsumerGroup
+type ConsumerGroupAtomicMap struct {
+	mu  sync.Mutex
+	val atomic.Value
+}
+
+type _ConsumerGroupMap map[string]*ConsumerGroup
+
+// NewConsumerGroupAtomicMap returns a new initialized ConsumerGroupAtomicMap
+func NewConsumerGroupAtomicMap() *ConsumerGroupAtomicMap {
+	am := &ConsumerGroupAtomicMap{}
+	am.val.Store(make(_ConsumerGroupMap, 0))
+	return am
+}
+
+// Get returns a pointer to ConsumerGroup for a given key
+func (am *ConsumerGroupAtomicMap) Get(key string) (value *ConsumerGroup, ok bool) {
+	value, ok = am.val.Load().(_ConsumerGroupMap)[key]
+	return value, ok
+}
+
+// GetAll returns the underlying map of pointers to ConsumerGroup
+// this map must NOT be modified, to change the map safely use the Set and Delete
+// functions and Get the value again
+func (am *ConsumerGroupAtomicMap) GetAll() map[string]*ConsumerGroup {
+	return am.val.Load().(_ConsumerGroupMap)
+}
+
+// Len returns the number of elements in the map
+func (am *ConsumerGroupAtomicMap) Len() int {
+	return len(am.val.Load().(_ConsumerGroupMap))
+}
+
+// Set inserts in the map a pointer to ConsumerGroup under a given key
+func (am *ConsumerGroupAtomicMap) Set(key string, value *ConsumerGroup) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	m1 := am.val.Load().(_ConsumerGroupMap)
+	m2 := make(_ConsumerGroupMap, len(m1)+1)
+	for k, v := range m1 {
+		m2[k] = v
+	}
+
+	m2[key] = value
+	am.val.Store(m2)
+	return
+}
+
+// Delete removes the pointer to ConsumerGroup under key from the map
+func (am *ConsumerGroupAtomicMap) Delete(key string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	m1 := am.val.Load().(_ConsumerGroupMap)
+	_, ok := m1[key]
+	if !ok {
+		return
+	}
+
+	m2 := make(_ConsumerGroupMap, len(m1)-1)
+	for k, v := range m1 {
+		if k != key {
+			m2[k] = v
+		}
+	}
+
+	am.val.Store(m2)
+	return
+}
@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"sync"
+)
+
+// Durability defines when a write is acknowledged.
+type Durability string
+
+const (
+	// DurabilityBuffered acknowledges writes as soon as they are buffered in memory.
+	DurabilityBuffered Durability = "buffered"
+	// DurabilityFlushed acknowledges writes once they are written into the log segment.
+	DurabilityFlushed Durability = "flushed"
+	// DurabilitySynced acknowledges writes once the log segment is synced to disk.
+	DurabilitySynced Durability = "synced"
+)
+
+// ParseDurability parses a durability mode, empty strings
+// are valid and mean the default mode of the topic.
+func ParseDurability(s string) (Durability, error) {
+	d := Durability(s)
+	switch d {
+	case "", DurabilityBuffered, DurabilityFlushed, DurabilitySynced:
+		return d, nil
+	}
+
+	return "", ErrInvalidDurability
+}
+
+// flushWaiter is implemented by writers which buffer data in memory.
+type flushWaiter interface {
+	// WaitFlushed blocks until the given offset is written into the underlying writer.
+	WaitFlushed(ctx context.Context, offset int64) error
+}
+
+// WaitDurable blocks until the message at offset reaches the durability `d`,
+// or the durability of the topic if `d` is empty, or the context is done.
+// Concurrent writers waiting to be synced share the same fsync.
+func (t *Topic) WaitDurable(ctx context.Context, offset int64, d Durability) error {
+	if d == "" {
+		d = t.Settings().Durability
+	}
+
+	switch d {
+	case "", DurabilityBuffered:
+		return nil
+	case DurabilityFlushed, DurabilitySynced:
+	default:
+		return ErrInvalidDurability
+	}
+
+	t.mu.RLock()
+	w := t.writer
+	t.mu.RUnlock()
+
+	// writers replaced by a settings update are flushed on the way out
+	if fw, ok := w.(flushWaiter); ok {
+		if err := fw.WaitFlushed(ctx, offset); err != nil {
+			return err
+		}
+	}
+
+	if d == DurabilitySynced {
+		return t.syncer.Wait(ctx, offset)
+	}
+
+	return nil
+}
+
+// groupSyncer syncs a log to disk on behalf of many waiting writers, writers
+// arriving while a sync is in progress are all covered by the next single one.
+type groupSyncer struct {
+	sync   func() error
+	latest func() int64
+
+	mu     sync.Mutex
+	synced int64
+	round  *syncRound
+}
+
+type syncRound struct {
+	done chan struct{}
+	err  error
+}
+
+func newGroupSyncer(sync func() error, latest func() int64) *groupSyncer {
+	return &groupSyncer{
+		sync:   sync,
+		latest: latest,
+		synced: -1,
+	}
+}
+
+// Wait blocks until the given offset has been synced to disk.
+func (g *groupSyncer) Wait(ctx context.Context, offset int64) error {
+	g.mu.Lock()
+	for g.synced < offset {
+		if g.round == nil {
+			g.mu.Unlock()
+			if err := g.run(); err != nil {
+				return err
+			}
+
+			g.mu.Lock()
+			continue
+		}
+
+		round := g.round
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-round.done:
+		}
+
+		g.mu.Lock()
+		if round.err != nil && g.synced < offset {
+			g.mu.Unlock()
+			return round.err
+		}
+	}
+
+	g.mu.Unlock()
+	return nil
+}
+
+// run performs a sync round unless another writer started one meanwhile.
+func (g *groupSyncer) run() error {
+	g.mu.Lock()
+	if g.round != nil {
+		g.mu.Unlock()
+		return nil
+	}
+
+	round := &syncRound{done: make(chan struct{})}
+	g.round = round
+	g.mu.Unlock()
+
+	// everything written before the sync is covered by it
+	target := g.latest()
+	err := g.sync()
+
+	g.mu.Lock()
+	if err == nil && target > g.synced {
+		g.synced = target
+	}
+
+	round.err = err
+	g.round = nil
+	g.mu.Unlock()
+	close(round.done)
+	return err
+}
@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ninibe/bigduration"
+)
+
+func TestTopicWaitDurable(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	bd, err := bigduration.ParseBigDuration("50ms")
+	panicOn(err)
+
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		BatchNumMessages: 1000,
+		BatchInterval:    bd,
+		Durability:       DurabilityFlushed,
+	})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	messages := randMessageSet()
+	offset, err := topic.WriteMessage(messages[0])
+	panicOn(err)
+
+	// buffered returns right away
+	err = topic.WaitDurable(ctx, offset, DurabilityBuffered)
+	panicOn(err)
+
+	// topic default waits for the flusher
+	err = topic.WaitDurable(ctx, offset, "")
+	panicOn(err)
+	if topic.bl.Latest() < offset {
+		t.Errorf("Offset %d acknowledged before being flushed", offset)
+	}
+
+	offset, err = topic.WriteMessage(messages[1])
+	panicOn(err)
+	err = topic.WaitDurable(ctx, offset, DurabilitySynced)
+	panicOn(err)
+	if topic.bl.Latest() < offset || topic.syncer.synced < offset {
+		t.Errorf("Offset %d acknowledged before being synced", offset)
+	}
+
+	if err = topic.WaitDurable(ctx, offset, "eventually"); err != ErrInvalidDurability {
+		t.Errorf("Expected ErrInvalidDurability, got %v", err)
+	}
+
+	_, err = nl.CreateTopic(randStr(6), TopicSettings{Durability: "eventually"})
+	if err != ErrInvalidDurability {
+		t.Errorf("Expected ErrInvalidDurability, got %v", err)
+	}
+}
+
+func TestGroupSyncer(t *testing.T) {
+	t.Parallel()
+
+	var latest, syncs int64
+	release := make(chan struct{})
+	gs := newGroupSyncer(func() error {
+		atomic.AddInt64(&syncs, 1)
+		<-release
+		return nil
+	}, func() int64 {
+		return atomic.LoadInt64(&latest)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// first writer starts a sync and blocks on it
+	atomic.StoreInt64(&latest, 0)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		panicOn(gs.Wait(ctx, 0))
+	}()
+
+	for atomic.LoadInt64(&syncs) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// writers arriving meanwhile share the next sync
+	atomic.StoreInt64(&latest, 10)
+	for i := int64(1); i <= 10; i++ {
+		wg.Add(1)
+		go func(offset int64) {
+			defer wg.Done()
+			panicOn(gs.Wait(ctx, offset))
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if syncs != 2 {
+		t.Errorf("Expected 2 syncs, got %d", syncs)
+	}
+
+	// already synced offsets return without syncing
+	panicOn(gs.Wait(ctx, 5))
+	if syncs != 2 {
+		t.Errorf("Expected 2 syncs, got %d", syncs)
+	}
+
+	failing := newGroupSyncer(func() error {
+		return errors.New("disk on fire")
+	}, func() int64 { return 0 })
+
+	if err := failing.Wait(ctx, 0); err == nil {
+		t.Errorf("Sync error not returned")
+	}
+}
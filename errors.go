@@ -60,13 +60,37 @@ var (
 	ErrInvalidDuration = newErr(http.StatusBadRequest, "netlog: invalid duration")
 	// ErrInvalidCompression is returning when the compression type defined is unknown
 	ErrInvalidCompression = newErr(http.StatusBadRequest, "netlog: invalid compression type")
+	// ErrInvalidDurability is returned when the durability mode defined is unknown.
+	ErrInvalidDurability = newErr(http.StatusBadRequest, "netlog: invalid durability mode")
+	// ErrInvalidCleanupPolicy is returned when the cleanup policy defined is unknown.
+	ErrInvalidCleanupPolicy = newErr(http.StatusBadRequest, "netlog: invalid cleanup policy")
+	// ErrInvalidQuotaPolicy is returned when the quota policy defined is unknown.
+	ErrInvalidQuotaPolicy = newErr(http.StatusBadRequest, "netlog: invalid quota policy")
+	// ErrInvalidVersion is returned when the message format version is unknown.
+	ErrInvalidVersion = newErr(http.StatusBadRequest, "netlog: invalid message version")
+	// ErrCorruptMessage is returned when a received message is truncated or does not match its checksum.
+	ErrCorruptMessage = newErr(http.StatusBadRequest, "netlog: corrupt message")
+	// ErrInvalidExport is returned when importing data that is not a valid topic export.
+	ErrInvalidExport = newErr(http.StatusBadRequest, "netlog: invalid topic export")
+	// ErrDeltaLimits is returned when the next entry to stream does not fit the requested limits.
+	ErrDeltaLimits = newErr(http.StatusBadRequest, "netlog: entry exceeds stream limits")
+	// ErrNotAckScanner is returned when acking messages of a scanner not created in ack mode.
+	ErrNotAckScanner = newErr(http.StatusBadRequest, "netlog: scanner does not use acks")
 	// ErrTopicExists is returning when trying to create an already existing topic.
 	ErrTopicExists = newErr(http.StatusBadRequest, "netlog: topic exists")
+	// ErrGroupExists is returned when trying to create an already existing consumer group.
+	ErrGroupExists = newErr(http.StatusBadRequest, "netlog: consumer group exists")
+	// ErrInvalidGroupName is returned when a consumer group name has characters other than letters, digits, '-' and '_'.
+	ErrInvalidGroupName = newErr(http.StatusBadRequest, "netlog: invalid consumer group name")
 	// ErrEndOfTopic is returned when the reader has read all the way until the end of the topic.
 	ErrEndOfTopic = newErr(http.StatusNotFound, "netlog: end of topic")
 	// ErrTopicNotFound is returned when addressing an non-existing topic.
 	ErrTopicNotFound = newErr(http.StatusNotFound, "netlog: topic not found")
 
+	// ErrGroupNotFound is returned when addressing a non-existing consumer group.
+	ErrGroupNotFound = newErr(http.StatusNotFound, "netlog: consumer group not found")
+	// ErrMemberNotFound is returned when addressing a member that left its consumer group or timed out.
+	ErrMemberNotFound = newErr(http.StatusNotFound, "netlog: group member not found")
 	// ErrScannerNotFound is returning when using a non-existing scanner ID.
 	ErrScannerNotFound = newErr(http.StatusNotFound, "netlog: scanner not found")
 	// ErrOffsetNotFound is returning when the offset is no longer or not yet present in the topic.
@@ -76,12 +100,31 @@ var (
 	ErrCRC = newErr(http.StatusInternalServerError, "netlog: checksum error")
 	// ErrBusy is retuning when trying to close or delete a topic with readers attached to it.
 	ErrBusy = newErr(http.StatusConflict, "netlog: resource busy")
+	// ErrReplicaDiverged is returned when a replicated entry does not follow the latest offset of the topic.
+	ErrReplicaDiverged = newErr(http.StatusConflict, "netlog: replica diverged from leader")
+
+	// ErrReadOnly is returned when trying to write on a read-only follower.
+	ErrReadOnly = newErr(http.StatusForbidden, "netlog: read-only follower")
+
+	// ErrUnauthorized is returned when a request does not carry valid credentials.
+	ErrUnauthorized = newErr(http.StatusUnauthorized, "netlog: unauthorized")
+	// ErrForbidden is returned when the credentials of a request lack the rights needed on a topic.
+	ErrForbidden = newErr(http.StatusForbidden, "netlog: forbidden")
+
+	// ErrClosed is returned when writing into a NetLog being shut down.
+	ErrClosed = newErr(http.StatusServiceUnavailable, "netlog: shutting down")
+
+	// ErrQuotaExceeded is returned when writing into a topic over its size limit or the data quota.
+	ErrQuotaExceeded = newErr(http.StatusInsufficientStorage, "netlog: quota exceeded")
 )
 
 var errmap = map[error]NLError{
 	biglog.ErrBusy:     ErrBusy,
 	biglog.ErrNotFound: ErrOffsetNotFound,
 	io.EOF:             ErrEndOfTopic,
+
+	biglog.ErrNeedMoreBytes:   ErrDeltaLimits,
+	biglog.ErrNeedMoreOffsets: ErrDeltaLimits,
 }
 
 // ExtErr maps external errors, mostly BigLog errors to NetLog errors.
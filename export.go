@@ -0,0 +1,453 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// Topic exports are gzipped tar archives holding the manifest, the settings of the
+// topic, its persisted scanners and groups under readers/ and the data under data/
+// in chunks made of a DeltaHeader followed by the stored entries, like streams.
+const (
+	exportVersion      = 1
+	exportManifest     = "export.json"
+	exportReaders      = "readers/"
+	exportData         = "data/"
+	exportChunkPattern = exportData + "%020d"
+	exportChunkOffsets = 1000
+	exportChunkBytes   = 1024 * 1024
+)
+
+// manifest describes the content of an export.
+type manifest struct {
+	Version int    `json:"version"`
+	Topic   string `json:"topic"`
+	From    int64  `json:"from"`
+	To      int64  `json:"to"`
+}
+
+// Export writes into w a portable archive of the topic with the offsets from `from`
+// to `to`, both included, along with its settings and persisted scanners and groups.
+// A negative `to` exports until the latest offset. Message-sets are exported whole
+// even if they hold offsets outside the range.
+func (t *Topic) Export(w io.Writer, from, to int64) (err error) {
+	defer func() {
+		if err != nil {
+			log.Printf("warn: failed to export topic %q: %s", t.name, err)
+		}
+	}()
+
+	if err = t.FlushBuffered(); err != nil {
+		return err
+	}
+
+	if earliest := t.bl.Earliest(); from < earliest {
+		from = earliest
+	}
+
+	if latest := t.Latest(); to < 0 || to > latest {
+		to = latest
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	m := manifest{Version: exportVersion, Topic: t.name, From: from, To: to}
+	if err = exportJSON(tw, exportManifest, m); err != nil {
+		return err
+	}
+
+	if err = exportJSON(tw, settingsFile, t.Settings()); err != nil {
+		return err
+	}
+
+	if err = t.exportReaders(tw); err != nil {
+		return err
+	}
+
+	if from <= to {
+		if err = t.exportData(tw, from, to); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// exportReaders adds the files of the persisted scanners and groups.
+func (t *Topic) exportReaders(tw *tar.Writer) error {
+	files, err := ioutil.ReadDir(t.readersDir())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(t.readersDir(), f.Name()))
+		if err != nil {
+			return err
+		}
+
+		if err = exportFile(tw, exportReaders+f.Name(), int64(len(b))); err != nil {
+			return err
+		}
+
+		if _, err = tw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportData adds the stored entries from `from` to `to` in chunks.
+func (t *Topic) exportData(tw *tar.Writer, from, to int64) error {
+	st, err := biglog.NewStreamer(t.bl, from)
+	if err != nil && err != biglog.ErrEmbeddedOffset {
+		return ExtErr(err)
+	}
+
+	defer logClose(st)
+
+	// chunk limits, raised if the next entry holds offsets after `to` or is bigger than a chunk
+	limits := func(next int64) (int64, int64) {
+		if left := to - next + 1; left < exportChunkOffsets {
+			return left, exportChunkBytes
+		}
+
+		return exportChunkOffsets, exportChunkBytes
+	}
+
+	maxOffsets, maxBytes := limits(from)
+	for next := from; next <= to; {
+		delta, err := st.Get(maxOffsets, maxBytes)
+		switch err {
+		case biglog.ErrNeedMoreOffsets:
+			maxOffsets *= 2
+			continue
+		case biglog.ErrNeedMoreBytes:
+			maxBytes *= 2
+			continue
+		case io.EOF:
+			return nil
+		case nil:
+		default:
+			return ExtErr(err)
+		}
+
+		h := DeltaHeader{
+			Offset: delta.Offset(),
+			ODelta: delta.OffsetDelta(),
+			Size:   delta.Size(),
+		}
+
+		err = exportFile(tw, fmt.Sprintf(exportChunkPattern, h.Offset), deltaHeaderSize+h.Size)
+		if err == nil {
+			_, err = tw.Write(h.Bytes())
+		}
+
+		if err == nil {
+			_, err = io.Copy(tw, delta)
+		}
+
+		if pErr := st.Put(delta); err == nil {
+			err = pErr
+		}
+
+		if err != nil {
+			return err
+		}
+
+		next = h.Offset + h.ODelta
+		maxOffsets, maxBytes = limits(next)
+	}
+
+	return nil
+}
+
+func exportFile(tw *tar.Writer, name string, size int64) error {
+	return tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	})
+}
+
+func exportJSON(tw *tar.Writer, name string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err = exportFile(tw, name, int64(len(b))); err != nil {
+		return err
+	}
+
+	_, err = tw.Write(b)
+	return err
+}
+
+// ImportTopic creates a topic from an archive written by Topic.Export, named as the
+// exported topic if name is empty. Offsets are renumbered from 0 and the positions of
+// scanners and groups moved along, unless preserveOffsets is set in which case the
+// topic starts at the first offset exported.
+func (nl *NetLog) ImportTopic(name string, r io.Reader, preserveOffsets bool) (t *Topic, err error) {
+	if err = nl.writable(); err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		nl:       nl,
+		name:     name,
+		preserve: preserveOffsets,
+		readers:  make(map[string][]byte),
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		log.Printf("warn: failed to import topic %q: %s", imp.name, err)
+		// leave no half imported topics behind
+		if imp.topic != nil && err != ErrTopicExists {
+			if dErr := nl.DeleteTopic(imp.name, true); dErr != nil {
+				log.Printf("error: failed to delete topic %q: %s", imp.name, dErr)
+			}
+		}
+	}()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidExport
+	}
+
+	defer logClose(gz)
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, ErrInvalidExport
+		}
+
+		switch {
+		case hdr.Name == exportManifest:
+			if json.NewDecoder(tr).Decode(&imp.manifest) != nil {
+				err = ErrInvalidExport
+			} else if imp.manifest.Version != exportVersion {
+				err = ErrInvalidVersion
+			}
+		case hdr.Name == settingsFile:
+			imp.settings = new(TopicSettings)
+			if json.NewDecoder(tr).Decode(imp.settings) != nil {
+				err = ErrInvalidExport
+			}
+		case strings.HasPrefix(hdr.Name, exportReaders):
+			imp.readers[path.Base(hdr.Name)], err = ioutil.ReadAll(tr)
+		case strings.HasPrefix(hdr.Name, exportData):
+			err = imp.importChunk(tr)
+		default:
+			log.Printf("warn: unknown file in topic export: %s", hdr.Name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// exports without data
+	if imp.topic == nil {
+		if err = imp.create(imp.manifest.From); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = imp.topic.Sync(); err != nil {
+		return nil, err
+	}
+
+	if err = imp.restoreReaders(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("info: imported topic %q up to offset %d", imp.name, imp.topic.Latest())
+	return imp.topic, nil
+}
+
+// importer holds the state of a topic being imported.
+type importer struct {
+	nl       *NetLog
+	name     string
+	preserve bool
+	manifest manifest
+	settings *TopicSettings
+	readers  map[string][]byte
+	topic    *Topic
+	shifts   []offsetShift
+}
+
+// offsetShift maps a range of n exported offsets starting at `from` to the imported ones starting at `to`.
+type offsetShift struct {
+	from, to, n int64
+}
+
+// create creates the imported topic, which must follow the manifest and the settings.
+func (imp *importer) create(first int64) (err error) {
+	if imp.manifest.Version == 0 || imp.settings == nil {
+		return ErrInvalidExport
+	}
+
+	if imp.name == "" {
+		imp.name = imp.manifest.Topic
+	}
+
+	if imp.preserve {
+		imp.topic, err = imp.nl.CreateReplica(imp.name, *imp.settings, first)
+	} else {
+		imp.topic, err = imp.nl.CreateTopic(imp.name, *imp.settings)
+	}
+
+	return err
+}
+
+// importChunk writes the stored entries of a data chunk into the topic.
+func (imp *importer) importChunk(r io.Reader) error {
+	h, err := ReadDeltaHeader(r)
+	if err != nil {
+		return ErrInvalidExport
+	}
+
+	if imp.topic == nil {
+		if err = imp.create(h.Offset); err != nil {
+			return err
+		}
+	}
+
+	// offsets removed by compaction
+	if h.Size == 0 {
+		if imp.preserve {
+			return imp.topic.Replicate(h.Offset, nil, int(h.ODelta))
+		}
+
+		return nil
+	}
+
+	shift := offsetShift{from: h.Offset, to: imp.topic.Latest() + 1, n: h.ODelta}
+	lr := io.LimitReader(r, h.Size)
+	for next := h.Offset; next < h.Offset+h.ODelta; {
+		entry, err := ReadMessage(lr)
+		if err != nil {
+			return ErrInvalidExport
+		}
+
+		n, err := EntryOffsets(entry)
+		if err != nil {
+			return ErrInvalidExport
+		}
+
+		if imp.preserve {
+			err = imp.topic.Replicate(next, entry, n)
+		} else {
+			imp.topic.mu.RLock()
+			_, err = imp.topic.appendEntry(entry, n)
+			imp.topic.mu.RUnlock()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		next += int64(n)
+	}
+
+	imp.shifts = append(imp.shifts, shift)
+	return nil
+}
+
+// mapOffset returns the imported offset of the first message at or after an exported offset.
+func (imp *importer) mapOffset(offset int64) int64 {
+	if imp.preserve {
+		return offset
+	}
+
+	i := sort.Search(len(imp.shifts), func(i int) bool {
+		return offset < imp.shifts[i].from+imp.shifts[i].n
+	})
+
+	if i == len(imp.shifts) {
+		return imp.topic.Latest() + 1
+	}
+
+	s := imp.shifts[i]
+	if offset < s.from {
+		return s.to
+	}
+
+	return s.to + offset - s.from
+}
+
+// restoreReaders writes the files of the exported scanners
+// and groups at their imported positions and loads them.
+func (imp *importer) restoreReaders() error {
+	if len(imp.readers) == 0 {
+		return nil
+	}
+
+	t := imp.topic
+	if err := os.MkdirAll(t.readersDir(), 0755); err != nil {
+		return err
+	}
+
+	for fname, b := range imp.readers {
+		switch filepath.Ext(fname) {
+		case ".scanner":
+			if len(b) >= 8 {
+				last := int64(enc.Uint64(b[:8]))
+				enc.PutUint64(b[:8], uint64(imp.mapOffset(last+1)-1))
+			}
+		case groupExt:
+			if len(b) == 8 {
+				enc.PutUint64(b, uint64(imp.mapOffset(int64(enc.Uint64(b)))))
+			}
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(t.readersDir(), fname), b, 0644); err != nil {
+			return err
+		}
+	}
+
+	t.restorePersistedReaders()
+	return nil
+}
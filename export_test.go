@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{CompressionType: CompressionGzip})
+	panicOn(err)
+
+	var payloads [][]byte
+	for _, n := range []int{3, 1, 2, 1} {
+		var msgs []Message
+		for i := 0; i < n; i++ {
+			payloads = append(payloads, randData(10))
+			msgs = append(msgs, MessageFromPayload(payloads[len(payloads)-1]))
+		}
+
+		_, err = topic.WriteMessages(msgs)
+		panicOn(err)
+	}
+
+	ts, err := topic.NewScanner(0, true)
+	panicOn(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 4; i++ {
+		_, _, err = ts.Scan(ctx)
+		panicOn(err)
+	}
+
+	// persisted asynchronously
+	for last, _ := scannerFromFile(topic.scannerPath(ts.ID())); last != 3; {
+		time.Sleep(time.Millisecond)
+		last, _ = scannerFromFile(topic.scannerPath(ts.ID()))
+	}
+
+	// the message-set 4-5 is exported whole
+	buf := &bytes.Buffer{}
+	panicOn(topic.Export(buf, 3, 4))
+	export := buf.Bytes()
+
+	check := func(imported *Topic, first int64, next int64) {
+		if o, l := imported.bl.Oldest(), imported.Latest(); o != first || l != first+2 {
+			t.Errorf("Imported offsets %d-%d, expected %d-%d", o, l, first, first+2)
+		}
+
+		for i := int64(0); i < 3; i++ {
+			p, err2 := imported.Payload(first + i)
+			panicOn(err2)
+			if !bytes.Equal(p, payloads[3+i]) {
+				t.Errorf("Unexpected payload at offset %d", first+i)
+			}
+		}
+
+		its, err2 := imported.Scanner(ts.ID())
+		panicOn(err2)
+		if _, o, err3 := its.Scan(ctx); err3 != nil || o != next {
+			t.Errorf("Imported scanner at offset %d, expected %d: %v", o, next, err3)
+		}
+	}
+
+	nl2 := tempNetLog()
+	imported, err := nl2.ImportTopic("", bytes.NewReader(export), false)
+	panicOn(err)
+	if imported.Name() != topicName {
+		t.Errorf("Imported topic %q, expected %q", imported.Name(), topicName)
+	}
+
+	check(imported, 0, 1)
+	if imported.Settings().CompressionType != CompressionGzip {
+		t.Errorf("Settings not imported %+v", imported.Settings())
+	}
+
+	preserved, err := nl2.ImportTopic("preserved", bytes.NewReader(export), true)
+	panicOn(err)
+	check(preserved, 3, 4)
+
+	if _, err = nl2.ImportTopic(topicName, bytes.NewReader(export), false); err != ErrTopicExists {
+		t.Errorf("Expected ErrTopicExists, got %v", err)
+	}
+
+	if _, err = nl2.ImportTopic("invalid", strings.NewReader("not an export"), false); err != ErrInvalidExport {
+		t.Errorf("Expected ErrInvalidExport, got %v", err)
+	}
+
+	if _, err = nl2.Topic("invalid"); err != ErrTopicNotFound {
+		t.Errorf("Expected ErrTopicNotFound, got %v", err)
+	}
+
+	logClose(ts)
+	panicOn(nl.DeleteTopic(topicName, true))
+	panicOn(nl2.DeleteTopic(topicName, true))
+	panicOn(nl2.DeleteTopic("preserved", true))
+}
@@ -5,9 +5,13 @@
 package netlog
 
 import (
+	"bytes"
 	"context"
 	"hash/crc32"
+	"io"
+	"io/ioutil"
 	"strconv"
+	"time"
 
 	"github.com/ninibe/netlog/biglog"
 )
@@ -26,6 +30,18 @@ const (
 	// header doesn't match the length of the payload.
 	IntegrityLengthErr IntegrityErrorType = "length"
 
+	// IntegrityMetaErr is returned when the metadata of a version 1
+	// message can not be parsed or the format version is unknown.
+	IntegrityMetaErr IntegrityErrorType = "meta"
+
+	// IntegrityCompressionErr is returned when the payload of
+	// a message-set can not be decompressed.
+	IntegrityCompressionErr IntegrityErrorType = "compression"
+
+	// IntegrityCountErr is returned when the number of messages in
+	// a message-set doesn't match the offsets given to it in the index.
+	IntegrityCountErr IntegrityErrorType = "count"
+
 	// IntegrityUnknownErr is returned when data can not be read because
 	// of an underlying error reading the data.
 	IntegrityUnknownErr IntegrityErrorType = "unknown"
@@ -40,24 +56,69 @@ type IntegrityError struct {
 	Actual   string             `json:"actual"`
 }
 
+// IntegrityProgress reports how far an integrity check went.
+type IntegrityProgress struct {
+	// Offset is the last offset checked.
+	Offset int64 `json:"offset"`
+	// Latest is the latest offset of the topic.
+	Latest int64 `json:"latest"`
+	// Checked is the number of offsets checked so far.
+	Checked int64 `json:"checked"`
+	// Errors is the number of errors found so far.
+	Errors int `json:"errors"`
+	// Done is set in the last report, once the check finished.
+	Done bool `json:"done"`
+}
+
+// ProgressFunc is called by the IntegrityChecker to report its progress.
+type ProgressFunc func(p IntegrityProgress)
+
 // IntegrityChecker is used to check the integrity of an entire topic.
 type IntegrityChecker struct {
+	bl *biglog.BigLog
 	sc *biglog.Scanner
+
+	progress ProgressFunc
+	interval time.Duration
 }
 
 // NewIntegrityChecker creates a new integrity checker for a given topic.
 func NewIntegrityChecker(t *Topic, from int64) (*IntegrityChecker, error) {
-	sc, err := biglog.NewScanner(t.bl, from)
+	return NewLogIntegrityChecker(t.bl, from)
+}
+
+// NewLogIntegrityChecker creates a new integrity checker reading the BigLog of a topic
+// directly, usually opened read-only to check the topic without starting a server.
+func NewLogIntegrityChecker(bl *biglog.BigLog, from int64) (*IntegrityChecker, error) {
+	sc, err := biglog.NewScanner(bl, from)
 	if err != nil {
 		return nil, err
 	}
 
-	return &IntegrityChecker{sc: sc}, nil
+	return &IntegrityChecker{bl: bl, sc: sc}, nil
+}
+
+// OnProgress sets a function called during Check at most once every interval,
+// and once more when the check finishes. Useful to follow checks of large topics.
+func (ic *IntegrityChecker) OnProgress(interval time.Duration, fn ProgressFunc) {
+	ic.interval = interval
+	ic.progress = fn
 }
 
 // Check reads all data collecting errors which then returns.
+// Message-sets are decompressed and each one of their messages checked.
 // Is recommended to pass a cancellable context since this operation can be slow.
 func (ic *IntegrityChecker) Check(ctx context.Context) (errors []*IntegrityError) {
+	p := IntegrityProgress{Offset: -1}
+	last := time.Now()
+	defer func() {
+		if ic.progress != nil {
+			p.Errors, p.Done = len(errors), true
+			p.Latest = ic.bl.Latest()
+			ic.progress(p)
+		}
+	}()
+
 	for {
 		if len(errors) >= errLimit {
 			return errors
@@ -69,11 +130,22 @@ func (ic *IntegrityChecker) Check(ctx context.Context) (errors []*IntegrityError
 		default:
 		}
 
+		if ic.progress != nil && time.Since(last) >= ic.interval && p.Checked > 0 {
+			p.Errors, p.Latest = len(errors), ic.bl.Latest()
+			ic.progress(p)
+			last = time.Now()
+		}
+
 		m, o, d, err := ic.scan()
 		if err == ErrEndOfTopic {
 			return errors
 		}
 
+		if err == nil {
+			p.Offset = o + int64(d) - 1
+			p.Checked += int64(d)
+		}
+
 		if err != nil {
 			errors = append(errors, &IntegrityError{
 				Offset: o,
@@ -85,35 +157,168 @@ func (ic *IntegrityChecker) Check(ctx context.Context) (errors []*IntegrityError
 			continue
 		}
 
-		iErr := CheckMessageIntegrity(m, d)
-		if iErr != nil {
-			iErr.Offset = o
+		// offsets removed by compaction
+		if len(m) == 0 {
+			continue
+		}
+
+		errors = append(errors, CheckEntryIntegrity(m, o, d)...)
+	}
+}
+
+// CheckEntryIntegrity checks a stored entry holding `delta` offsets starting at offset `offset`,
+// which can be a single message, a sequence of uncompressed messages or a message-set. Errors
+// inside sequences and sets are reported at the offset of the message where they are found.
+func CheckEntryIntegrity(entry Message, offset int64, delta int) []*IntegrityError {
+	if delta > 1 && entry.Compression() == CompressionDefault {
+		return checkSequenceIntegrity(entry, offset, delta)
+	}
+
+	if iErr := CheckMessageIntegrity(entry, delta); iErr != nil {
+		iErr.Offset = offset
+		return []*IntegrityError{iErr}
+	}
+
+	if entry.Compression() != CompressionDefault {
+		return checkSetIntegrity(entry, offset, delta)
+	}
+
+	return nil
+}
+
+// checkSequenceIntegrity checks an entry made of a sequence of
+// `delta` uncompressed messages starting at offset `offset`.
+func checkSequenceIntegrity(seq Message, offset int64, delta int) (errors []*IntegrityError) {
+	r := bytes.NewReader(seq)
+	for i := 0; i < delta; i++ {
+		m, err := ReadMessage(r)
+		if err != nil {
+			return append(errors, &IntegrityError{
+				Offset:   offset + int64(i),
+				ODelta:   delta - i,
+				Type:     IntegrityLengthErr,
+				Expected: strconv.Itoa(delta),
+				Actual:   strconv.Itoa(i),
+			})
+		}
+
+		if iErr := CheckMessageIntegrity(m, 1); iErr != nil {
+			iErr.Offset = offset + int64(i)
+			errors = append(errors, iErr)
+		}
+	}
+
+	return errors
+}
+
+// checkSetIntegrity checks the messages packed in a message-set
+// holding `delta` offsets starting at offset `offset`.
+func checkSetIntegrity(set Message, offset int64, delta int) (errors []*IntegrityError) {
+	data, err := decompress(set)
+	if err != nil {
+		return append(errors, &IntegrityError{
+			Offset: offset,
+			ODelta: delta,
+			Type:   IntegrityCompressionErr,
+			Actual: err.Error(),
+		})
+	}
+
+	var i int
+	for pos := 0; pos < len(data); i++ {
+		m := Message(data[pos:])
+		if len(m) < headerSize || m.Size() > len(m) {
+			// the rest of the set can not be framed
+			left := delta - i
+			if left < 1 {
+				left = 1
+			}
+
+			return append(errors, &IntegrityError{
+				Offset:   offset + int64(i),
+				ODelta:   left,
+				Type:     IntegrityLengthErr,
+				Expected: strconv.Itoa(delta),
+				Actual:   strconv.Itoa(i),
+			})
+		}
+
+		m = m[:m.Size()]
+		pos += len(m)
+
+		if m.Compression() != CompressionDefault {
+			errors = append(errors, &IntegrityError{
+				Offset: offset + int64(i),
+				ODelta: 1,
+				Type:   IntegrityCompressionErr,
+				Actual: ErrInvalidCompression.Error(),
+			})
+
+			continue
+		}
+
+		if iErr := CheckMessageIntegrity(m, 1); iErr != nil {
+			iErr.Offset = offset + int64(i)
 			errors = append(errors, iErr)
 		}
 	}
+
+	if i != delta {
+		errors = append(errors, &IntegrityError{
+			Offset:   offset,
+			ODelta:   delta,
+			Type:     IntegrityCountErr,
+			Expected: strconv.Itoa(delta),
+			Actual:   strconv.Itoa(i),
+		})
+	}
+
+	return errors
+}
+
+// decompress returns the packed messages of a message-set.
+func decompress(set Message) ([]byte, error) {
+	r, err := decompressor(set.Payload(), set.Compression())
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := r.(io.Closer); ok {
+		defer logClose(c)
+	}
+
+	return ioutil.ReadAll(r)
 }
 
-// CheckMessageIntegrity checks the integrity of a single message
+// CheckMessageIntegrity checks the integrity of a single message, message-sets
+// are checked as a whole without unpacking them, see CheckEntryIntegrity.
 func CheckMessageIntegrity(m Message, delta int) *IntegrityError {
 	if !m.ChecksumOK() {
 		return &IntegrityError{
 			ODelta:   delta,
 			Type:     IntegrityChecksumErr,
-			Expected: strconv.Itoa(int(crc32.ChecksumIEEE(m.Bytes()))),
+			Expected: strconv.Itoa(int(crc32.ChecksumIEEE(m.body()))),
 			Actual:   strconv.Itoa(int(m.CRC32())),
 		}
 	}
 
-	if int(m.PLength()) != len(m.Payload()) {
+	if int(m.PLength()) != len(m.body()) {
 		return &IntegrityError{
 			ODelta:   delta,
 			Type:     IntegrityLengthErr,
 			Expected: strconv.Itoa(int(m.PLength())),
-			Actual:   strconv.Itoa(len(m.Payload())),
+			Actual:   strconv.Itoa(len(m.body())),
+		}
+	}
+
+	if _, _, err := m.meta(); err != nil {
+		return &IntegrityError{
+			ODelta: delta,
+			Type:   IntegrityMetaErr,
+			Actual: err.Error(),
 		}
 	}
 
-	// TODO check compressed integrity
 	return nil
 }
 
@@ -2,6 +2,7 @@ package netlog
 
 import (
 	"context"
+	"hash/crc32"
 	"testing"
 )
 
@@ -43,6 +44,18 @@ func TestCheckMessageIntegrity(t *testing.T) {
 	if i2.Type != IntegrityChecksumErr {
 		t.Errorf("Invalid integrity error type, expected %s vs actual %s", IntegrityChecksumErr, i2.Type)
 	}
+
+	m = MessageWithMeta(data, MessageMeta{Key: []byte("key")})
+	if CheckMessageIntegrity(m, 1) != nil {
+		t.Errorf("Integrity check failed with valid v1 message")
+	}
+
+	// unknown version
+	m[compverPos] = 7 << 4
+	i3 := CheckMessageIntegrity(m, 1)
+	if i3 == nil || i3.Type != IntegrityMetaErr {
+		t.Errorf("Invalid integrity error, expected %s vs actual %+v", IntegrityMetaErr, i3)
+	}
 }
 
 func TestTopicIntegrity(t *testing.T) {
@@ -91,3 +104,56 @@ func TestTopicIntegrity(t *testing.T) {
 		t.Errorf("Expected error on offset %d got %d", 7, iErrs[1].Offset)
 	}
 }
+
+func TestSetIntegrity(t *testing.T) {
+	t.Parallel()
+
+	msgs := randMessageSet()
+	// corrupt crc header of the third message
+	enc.PutUint32(msgs[2][crc32Pos:crc32Pos+4], uint32(20))
+
+	for _, comp := range []CompressionType{CompressionNone, CompressionGzip, CompressionSnappy} {
+		set := MessageSet(msgs, comp)
+		iErrs := CheckEntryIntegrity(set, 10, len(msgs))
+		if len(iErrs) != 1 || iErrs[0].Type != IntegrityChecksumErr || iErrs[0].Offset != 12 {
+			t.Errorf("Expected checksum error at offset 12 with compression %d, got %+v", comp, iErrs)
+		}
+
+		iErrs = CheckEntryIntegrity(set, 10, len(msgs)+1)
+		if len(iErrs) != 2 || iErrs[1].Type != IntegrityCountErr {
+			t.Errorf("Expected count error with compression %d, got %+v", comp, iErrs)
+		}
+	}
+
+	// corrupt the compressed payload keeping the outer checksum valid
+	set := MessageSet(msgs, CompressionGzip)
+	set[len(set)-5]++
+	enc.PutUint32(set[crc32Pos:crc32Pos+4], crc32.ChecksumIEEE(set.body()))
+	iErrs := CheckEntryIntegrity(set, 10, len(msgs))
+	if len(iErrs) != 1 || iErrs[0].Type != IntegrityCompressionErr || iErrs[0].Offset != 10 {
+		t.Errorf("Expected compression error at offset 10, got %+v", iErrs)
+	}
+
+	nl := tempNetLog()
+	topic, err := nl.CreateTopic(randStr(6), TopicSettings{})
+	panicOn(err)
+	_, err = topic.WriteN(MessageSet(msgs, CompressionSnappy), len(msgs))
+	panicOn(err)
+
+	var progress []IntegrityProgress
+	iErrs, err = topic.CheckIntegrityProgress(context.Background(), 0, 0, func(p IntegrityProgress) {
+		progress = append(progress, p)
+	})
+
+	panicOn(err)
+	if len(iErrs) != 1 || iErrs[0].Offset != 2 {
+		t.Errorf("Expected checksum error at offset 2, got %+v", iErrs)
+	}
+
+	last := progress[len(progress)-1]
+	if !last.Done || last.Checked != int64(len(msgs)) || last.Errors != 1 || last.Latest != int64(len(msgs)-1) {
+		t.Errorf("Unexpected final progress %+v", last)
+	}
+
+	panicOn(nl.DeleteTopic(topic.Name(), true))
+}
@@ -9,6 +9,8 @@ import (
 	"compress/gzip"
 	"hash/crc32"
 	"io"
+	"sort"
+	"time"
 
 	"github.com/golang/snappy"
 )
@@ -21,6 +23,15 @@ const (
 	headerSize = payloadPos
 )
 
+// Message format versions, stored in the high bits of CompVer.
+const (
+	// MessageV0 messages contain only the payload after the header.
+	MessageV0 uint8 = 0
+	// MessageV1 messages start their body with a timestamp, a key and a list
+	// of headers before the payload. The checksum covers the whole body.
+	MessageV1 uint8 = 1
+)
+
 // CompressionType indicates a type of compression for message sets
 type CompressionType uint8
 
@@ -44,6 +55,61 @@ func MessageFromPayload(p []byte) Message {
 	return Message(buf)
 }
 
+// MessageMeta is the metadata carried by version 1 messages.
+type MessageMeta struct {
+	Key       []byte            `json:"key,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// MessageWithMeta returns a version 1 message with the given metadata and payload.
+// A zero timestamp is stored as is, producers should set it to the creation time.
+func MessageWithMeta(p []byte, meta MessageMeta) Message {
+	names := make([]string, 0, len(meta.Headers))
+	size := 8 + 4 + len(meta.Key) + 2
+	for name, value := range meta.Headers {
+		names = append(names, name)
+		size += 2 + len(name) + 4 + len(value)
+	}
+
+	// sorted for the same metadata to always produce the same bytes
+	sort.Strings(names)
+
+	var ts int64
+	if !meta.Timestamp.IsZero() {
+		ts = meta.Timestamp.UnixNano()
+	}
+
+	body := make([]byte, 0, size+len(p))
+	body = appendUint64(body, uint64(ts))
+	body = appendUint32(body, uint32(len(meta.Key)))
+	body = append(body, meta.Key...)
+	body = appendUint16(body, uint16(len(names)))
+	for _, name := range names {
+		body = appendUint16(body, uint16(len(name)))
+		body = append(body, name...)
+		body = appendUint32(body, uint32(len(meta.Headers[name])))
+		body = append(body, meta.Headers[name]...)
+	}
+	body = append(body, p...)
+
+	m := MessageFromPayload(body)
+	m[compverPos] = MessageV1 << 4
+	return m
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
 // MessageSet returns a new message with a batch of compressed messages as payload
 // Compression will compress the payload and set the compression header, please be ware that compression
 // at this level is only meant for batching several messages into a single message-set in increase throughput.
@@ -110,56 +176,179 @@ func (m *Message) Size() int {
 	return int(m.PLength() + headerSize)
 }
 
-// CRC32 returns the checksum of the payload.
+// CRC32 returns the checksum of the body.
 func (m *Message) CRC32() uint32 {
 	return enc.Uint32(m.Bytes()[crc32Pos : crc32Pos+4])
 }
 
-// PLength returns the length (bytes) of the payload.
+// PLength returns the length (bytes) of the body, which
+// for version 1 messages includes the metadata.
 func (m *Message) PLength() uint32 {
 	return enc.Uint32(m.Bytes()[plenthPos : plenthPos+4])
 }
 
-// Payload returns the data bytes.
+// Payload returns the data bytes. Version 1 messages
+// with malformed metadata return a nil payload.
 func (m *Message) Payload() []byte {
+	if m.Version() == MessageV0 {
+		return m.body()
+	}
+
+	_, n, err := m.meta()
+	if err != nil {
+		return nil
+	}
+
+	return m.body()[n:]
+}
+
+// Meta returns the key, timestamp and headers of the message.
+// Version 0 messages return empty metadata.
+func (m *Message) Meta() (MessageMeta, error) {
+	meta, _, err := m.meta()
+	return meta, err
+}
+
+// Key returns the key of the message if any.
+func (m *Message) Key() []byte {
+	meta, _, _ := m.meta()
+	return meta.Key
+}
+
+// Timestamp returns the producer timestamp of the message if any.
+func (m *Message) Timestamp() time.Time {
+	meta, _, _ := m.meta()
+	return meta.Timestamp
+}
+
+// Headers returns the headers of the message if any.
+func (m *Message) Headers() map[string]string {
+	meta, _, _ := m.meta()
+	return meta.Headers
+}
+
+// body returns everything after the header, metadata included.
+func (m *Message) body() []byte {
 	return m.Bytes()[headerSize:]
 }
 
+// meta parses the metadata and returns it along with its length in bytes.
+func (m *Message) meta() (meta MessageMeta, n int, err error) {
+	switch m.Version() {
+	case MessageV0:
+		return meta, 0, nil
+	case MessageV1:
+	default:
+		return meta, 0, ErrInvalidVersion
+	}
+
+	b := m.body()
+	next := func(l int) []byte {
+		if err != nil || len(b)-n < l {
+			err = ErrCorruptMessage
+			return nil
+		}
+		n += l
+		return b[n-l : n]
+	}
+
+	if ts := next(8); ts != nil && enc.Uint64(ts) != 0 {
+		meta.Timestamp = time.Unix(0, int64(enc.Uint64(ts)))
+	}
+
+	if l := next(4); l != nil && enc.Uint32(l) > 0 {
+		meta.Key = next(int(enc.Uint32(l)))
+	}
+
+	var count int
+	if c := next(2); c != nil {
+		count = int(enc.Uint16(c))
+	}
+
+	if count > 0 {
+		meta.Headers = make(map[string]string, count)
+	}
+
+	for i := 0; i < count && err == nil; i++ {
+		var name, value []byte
+		if l := next(2); l != nil {
+			name = next(int(enc.Uint16(l)))
+		}
+		if l := next(4); l != nil {
+			value = next(int(enc.Uint32(l)))
+		}
+		meta.Headers[string(name)] = string(value)
+	}
+
+	if err != nil {
+		return MessageMeta{}, 0, err
+	}
+
+	return meta, n, nil
+}
+
 // Bytes returns the entire message casted back to bytes.
 func (m *Message) Bytes() []byte {
 	return []byte(*m)
 }
 
-// ChecksumOK recalculates the CRC from the payload
+// ChecksumOK recalculates the CRC from the body
 // and compares it with the one stored in the header
 func (m *Message) ChecksumOK() bool {
-	return crc32.ChecksumIEEE(m.Payload()) == m.CRC32()
+	return crc32.ChecksumIEEE(m.body()) == m.CRC32()
 }
 
 // ReadMessage reads a message from r and returns it
 // if the message is compressed it does not attempt to unpack the contents.
+// Messages of every version share the same header so they are framed alike.
 func ReadMessage(r io.Reader) (entry Message, err error) {
 
 	// TODO buffer pool?
 	header := make([]byte, headerSize)
-	n, err := r.Read(header)
-	if err != nil {
-		return entry, err
+	_, err = io.ReadFull(r, header)
+	if err == io.ErrUnexpectedEOF {
+		return entry, io.ErrShortBuffer
 	}
 
-	if n != headerSize {
-		return entry, io.ErrShortBuffer
+	if err != nil {
+		return entry, err
 	}
 
 	entry = Message(header)
 	buf := make([]byte, entry.Size())
 	copy(buf, header)
-	_, _ = r.Read(buf[headerSize:])
+	_, err = io.ReadFull(r, buf[headerSize:])
 	entry = Message(buf)
 
 	return entry, err
 }
 
+// ReadMessages reads all messages from r until EOF. ErrCorruptMessage is returned
+// if any message is truncated, does not match its checksum or has malformed metadata, and ErrInvalidCompression
+// if it is a message-set, since sets can not be nested into other sets.
+func ReadMessages(r io.Reader) (msgs []Message, err error) {
+	for {
+		m, err := ReadMessage(r)
+		if err == io.EOF {
+			return msgs, nil
+		}
+
+		if err != nil || !m.ChecksumOK() {
+			return nil, ErrCorruptMessage
+		}
+
+		if _, _, err = m.meta(); err != nil {
+			return nil, ErrCorruptMessage
+		}
+
+		if m.Compression() != CompressionDefault {
+			return nil, ErrInvalidCompression
+		}
+
+		msgs = append(msgs, m)
+	}
+}
+
 // Unpack takes a message-set and returns a slice with the component messages.
 func Unpack(set Message) ([]Message, error) {
 	if set.Compression() > 0 {
@@ -174,23 +363,20 @@ func Unpack(set Message) ([]Message, error) {
 	return unpack(set, CompressionNone)
 }
 
-func unpack(data []byte, comp CompressionType) (msgs []Message, err error) {
-	var r io.Reader = bytes.NewReader(data)
-
-	switch comp {
-	case 0: // not a set
-	case CompressionNone:
-	case CompressionGzip:
-		r, err = gzip.NewReader(r)
-		if err != nil {
-			return nil, err
-		}
+// EntryOffsets returns the number of offsets held by a stored entry.
+func EntryOffsets(entry Message) (int, error) {
+	if entry.Compression() == CompressionDefault {
+		return 1, nil
+	}
 
-	case CompressionSnappy:
-		r = snappy.NewReader(r)
+	msgs, err := Unpack(entry)
+	return len(msgs), err
+}
 
-	default:
-		return nil, ErrInvalidCompression
+func unpack(data []byte, comp CompressionType) (msgs []Message, err error) {
+	r, err := decompressor(data, comp)
+	if err != nil {
+		return nil, err
 	}
 
 	// close reader if possible on exit
@@ -217,6 +403,29 @@ func unpack(data []byte, comp CompressionType) (msgs []Message, err error) {
 	return msgs, err
 }
 
+// decompressor returns a reader of the messages packed in the payload of a set.
+func decompressor(data []byte, comp CompressionType) (r io.Reader, err error) {
+	r = bytes.NewReader(data)
+
+	switch comp {
+	case 0: // not a set
+	case CompressionNone:
+	case CompressionGzip:
+		r, err = gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+	case CompressionSnappy:
+		r = snappy.NewReader(r)
+
+	default:
+		return nil, ErrInvalidCompression
+	}
+
+	return r, nil
+}
+
 // NopWCloser returns a WriteCloser with a no-op
 // Close method wrapping the provided Writer w.
 func NopWCloser(w io.Writer) io.WriteCloser {
@@ -5,53 +5,131 @@
 package netlog
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
 )
 
-type nWriter interface {
-	WriteN(p []byte, n int) (written int, err error)
+// appender is the underlying storage of a topic, *biglog.BigLog implements it.
+type appender interface {
+	// Append writes an entry of n offsets returning the offset of the first one.
+	Append(p []byte, n int) (offset int64, err error)
+	// Latest returns the latest offset written.
+	Latest() int64
 }
 
 type messageBuffer struct {
-	writer nWriter
+	writer appender
 	comp   CompressionType
 
 	mu       sync.Mutex
 	buff     []Message
 	buffered int
 	messages int
+	interval time.Duration
+	flushed  *flushSignal
+	err      error // of the first failed flush, writes are rejected after it
 	stopChan chan struct{}
+	stopOnce sync.Once
+	metrics  *topicMetrics
 }
 
-func newMessageBuffer(w nWriter, settings TopicSettings) *messageBuffer {
+// flushSignal is closed once the messages buffered when it was created are flushed.
+type flushSignal struct {
+	done chan struct{}
+	err  error
+}
+
+func newFlushSignal() *flushSignal {
+	return &flushSignal{done: make(chan struct{})}
+}
+
+func newMessageBuffer(w appender, settings TopicSettings, tm *topicMetrics) *messageBuffer {
+	// batches are always written as message sets
+	comp := settings.CompressionType
+	if comp == CompressionDefault {
+		comp = CompressionNone
+	}
 
 	m := &messageBuffer{
 		writer:   w,
 		buff:     make([]Message, settings.BatchNumMessages),
-		comp:     settings.CompressionType,
+		comp:     comp,
 		messages: settings.BatchNumMessages,
+		interval: settings.BatchInterval.Duration(),
+		flushed:  newFlushSignal(),
 		stopChan: make(chan struct{}),
+		metrics:  tm,
 	}
 
-	go m.launchFlusher(settings.BatchInterval.Duration())
+	go m.launchFlusher(m.interval)
 
 	return m
 }
 
 // Write implements the io.Writer interface for the messageBuffer
 func (m *messageBuffer) Write(p []byte) (n int, err error) {
+	_, err = m.Append(p, 1)
+	return len(p), err
+}
+
+// Append buffers a single message returning the offset it will be written at once flushed.
+// Entries of more than one offset are written right away after flushing the buffer.
+// Once a flush fails the messages buffered are lost and every write fails with its error,
+// so their offsets are never handed out again.
+func (m *messageBuffer) Append(p []byte, n int) (offset int64, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.err != nil {
+		return -1, m.err
+	}
+
+	if n != 1 {
+		return m.appendEntry(p, n)
+	}
+
+	// the topic appends to the log only through the buffer, which flushes before
+	// writing entries as is, so the offset is known before the flush
+	offset = m.latest() + 1
 	m.buff[m.buffered] = Message(p)
 	m.buffered++
 	if m.buffered == m.messages {
 		err = m.flush()
 	}
 
-	return len(p), err
+	return offset, err
+}
+
+// AppendEntry writes an entry of n offsets as is after flushing the buffer.
+func (m *messageBuffer) AppendEntry(p []byte, n int) (offset int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.appendEntry(p, n)
+}
+
+func (m *messageBuffer) appendEntry(p []byte, n int) (offset int64, err error) {
+	if m.err != nil {
+		return -1, m.err
+	}
+
+	if err = m.flush(); err != nil {
+		return -1, err
+	}
+
+	return m.writer.Append(p, n)
+}
+
+// Latest returns the latest offset written including buffered messages.
+func (m *messageBuffer) Latest() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latest()
+}
+
+func (m *messageBuffer) latest() int64 {
+	return m.writer.Latest() + int64(m.buffered)
 }
 
 // Flush flushes the data from the buffer into the underlying writer
@@ -61,13 +139,54 @@ func (m *messageBuffer) Flush() (err error) {
 	return m.flush()
 }
 
+// WaitFlushed blocks until the message at offset is written into the underlying writer,
+// or returns the error of the flush that lost it. Without a flush interval the buffer
+// is flushed right away.
+func (m *messageBuffer) WaitFlushed(ctx context.Context, offset int64) error {
+	m.mu.Lock()
+	if m.writer.Latest() >= offset {
+		m.mu.Unlock()
+		return nil
+	}
+
+	// offsets after the latest written were handed out before the failed flush
+	if m.err != nil {
+		m.mu.Unlock()
+		return m.err
+	}
+
+	if m.interval == 0 {
+		err := m.flush()
+		m.mu.Unlock()
+		return err
+	}
+
+	flushed := m.flushed
+	m.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushed.done:
+		return flushed.err
+	}
+}
+
 func (m *messageBuffer) flush() (err error) {
 	if m.buffered == 0 {
 		return nil
 	}
 
 	defer func() {
+		if err != nil {
+			log.Printf("alert: flush failed, %d buffered messages lost: %s", m.buffered, err)
+			m.err = err
+		}
+
 		m.buffered = 0
+		m.flushed.err = err
+		close(m.flushed.done)
+		m.flushed = newFlushSignal()
 	}()
 
 	var data Message
@@ -77,17 +196,25 @@ func (m *messageBuffer) flush() (err error) {
 		data = MessageSet(m.buff[:m.buffered], m.comp)
 	}
 
-	_, err = m.writer.WriteN(data.Bytes(), m.buffered)
+	start := time.Now()
+	_, err = m.writer.Append(data.Bytes(), m.buffered)
+	m.metrics.flushed(start, m.buffered)
 	return err
 }
 
-// Close releases all resources
+// Close flushes the buffered messages and stops the periodic flushes.
+// Closing more than once only flushes the messages buffered since.
 func (m *messageBuffer) Close() (err error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.stopChan <- struct{}{}
-	m.writer = nil
-	return nil
+	err = m.flush()
+	m.mu.Unlock()
+
+	// the flusher might be waiting for the lock
+	if m.interval > 0 {
+		m.stopOnce.Do(func() { m.stopChan <- struct{}{} })
+	}
+
+	return err
 }
 
 func (m *messageBuffer) launchFlusher(d time.Duration) {
@@ -96,12 +223,11 @@ func (m *messageBuffer) launchFlusher(d time.Duration) {
 	}
 
 	ticker := time.NewTicker(d)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			if err := m.Flush(); err != nil {
-				log.Printf("alert: flush failed: %s", err)
-			}
+			_ = m.Flush()
 			continue
 		case <-m.stopChan:
 			close(m.stopChan)
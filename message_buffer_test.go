@@ -5,6 +5,8 @@
 package netlog
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -29,14 +31,18 @@ func TestMessageBuffer(t *testing.T) {
 			BatchInterval:    bd,
 			BatchNumMessages: batchSize,
 			CompressionType:  comp,
-		})
+		}, nil)
 
 		data := randMessageSet()
 		for k := range data {
-			_, err := mb.Write(data[k])
+			offset, err := mb.Append(data[k], 1)
 			if err != nil {
 				t.Errorf("Failed to write to message buffer %s", err)
 			}
+
+			if offset != int64(k) {
+				t.Errorf("Message buffer assigned offset %d. Expected %d", offset, k)
+			}
 		}
 
 		expWrites := int(len(data) / batchSize)
@@ -69,7 +75,7 @@ func TestMessageBufferFlusher(t *testing.T) {
 		BatchInterval:    bd,
 		BatchNumMessages: 100000, // something unreachable
 		CompressionType:  CompressionGzip,
-	})
+	}, nil)
 
 	// Give the flusher a head start
 	time.Sleep(bd.Duration() / 2)
@@ -92,6 +98,46 @@ func TestMessageBufferFlusher(t *testing.T) {
 	if nw.Writes() != 1 {
 		t.Errorf("Message buffer not flushed in time")
 	}
+
+	panicOn(mb.Close())
+	panicOn(mb.Close())
+}
+
+func TestMessageBufferFlushError(t *testing.T) {
+	t.Parallel()
+
+	w := &testNWriter{}
+	mb := newMessageBuffer(w, TopicSettings{BatchNumMessages: 3}, nil)
+
+	data := randMessageSet()
+	for k := 0; k < 2; k++ {
+		_, err := mb.Append(data[k], 1)
+		panicOn(err)
+	}
+
+	// the flush of the third message fails
+	w.err = errors.New("disk failure")
+	if _, err := mb.Append(data[2], 1); err != w.err {
+		t.Errorf("Expected flush error appending, got %v", err)
+	}
+
+	// the messages lost are never reported as flushed
+	for o := int64(0); o < 3; o++ {
+		if err := mb.WaitFlushed(context.Background(), o); err != w.err {
+			t.Errorf("Expected flush error waiting for offset %d, got %v", o, err)
+		}
+	}
+
+	// nor are their offsets handed out again
+	if _, err := mb.Append(data[3], 1); err != w.err {
+		t.Errorf("Expected flush error appending after a failed flush, got %v", err)
+	}
+
+	if _, err := mb.AppendEntry(data[3], 2); err != w.err {
+		t.Errorf("Expected flush error appending an entry after a failed flush, got %v", err)
+	}
+
+	panicOn(mb.Close())
 }
 
 type testNWriter struct {
@@ -99,15 +145,29 @@ type testNWriter struct {
 	writes  int
 	offsets int
 	data    [][]byte
+	err     error
 }
 
-func (nw *testNWriter) WriteN(p []byte, n int) (written int, err error) {
+func (nw *testNWriter) Append(p []byte, n int) (offset int64, err error) {
 	nw.mutex.Lock()
+	if nw.err != nil {
+		nw.mutex.Unlock()
+		return -1, nw.err
+	}
+
+	offset = int64(nw.offsets)
 	nw.writes++
 	nw.offsets += n
 	nw.data = append(nw.data, p)
 	nw.mutex.Unlock()
-	return len(p), nil
+	return offset, nil
+}
+
+func (nw *testNWriter) Latest() int64 {
+	nw.mutex.Lock()
+	defer nw.mutex.Unlock()
+
+	return int64(nw.offsets) - 1
 }
 
 func (nw *testNWriter) Writes() int {
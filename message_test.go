@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"hash/crc32"
 	"math/rand"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 )
@@ -41,6 +43,61 @@ func testMessage(t *testing.T, data []byte, msg Message) {
 	}
 }
 
+func TestMessageWithMeta(t *testing.T) {
+	t.Parallel()
+
+	data := randData(rand.Intn(990) + 10)
+	meta := MessageMeta{
+		Key:       []byte("user-42"),
+		Timestamp: time.Unix(0, time.Now().UnixNano()),
+		Headers:   map[string]string{"Trace-Id": "abc", "Empty": ""},
+	}
+
+	msg := MessageWithMeta(data, meta)
+	if msg.Version() != MessageV1 || msg.Compression() != CompressionDefault {
+		t.Errorf("Bad Message. Invalid version %d or compression %d", msg.Version(), msg.Compression())
+	}
+
+	if !msg.ChecksumOK() {
+		t.Error("Bad Message. Self checksum failed.")
+	}
+
+	if !bytes.Equal(data, msg.Payload()) {
+		t.Errorf("Bad Message. Payload not equal to original data.")
+	}
+
+	got, err := msg.Meta()
+	panicOn(err)
+	if !bytes.Equal(got.Key, meta.Key) || !got.Timestamp.Equal(meta.Timestamp) || !reflect.DeepEqual(got.Headers, meta.Headers) {
+		t.Errorf("Bad Message. Metadata not equal to original.\n Got: %+v\n Exp: %+v\n", got, meta)
+	}
+
+	// v0 and v1 messages can be mixed in sets
+	msgs := []Message{MessageFromPayload(data), msg}
+	unpacked, err := Unpack(MessageSet(msgs, CompressionGzip))
+	panicOn(err)
+	if len(unpacked) != 2 || !bytes.Equal(unpacked[1].Key(), meta.Key) || unpacked[0].Key() != nil {
+		t.Errorf("Metadata lost unpacking set")
+	}
+
+	read, err := ReadMessages(bytes.NewReader(append(msgs[0], msgs[1]...)))
+	panicOn(err)
+	if len(read) != 2 || !bytes.Equal(read[1].Payload(), data) {
+		t.Errorf("Failed to read mixed versions")
+	}
+
+	// truncated metadata with a valid checksum
+	bad := MessageFromPayload([]byte{0, 0, 0})
+	bad[compverPos] = MessageV1 << 4
+	if _, err = bad.Meta(); err != ErrCorruptMessage || bad.Payload() != nil {
+		t.Errorf("Expected ErrCorruptMessage, got %v", err)
+	}
+
+	if _, err = ReadMessages(bytes.NewReader(bad)); err != ErrCorruptMessage {
+		t.Errorf("Expected ErrCorruptMessage, got %v", err)
+	}
+}
+
 func TestUnpackSequence(t *testing.T) {
 	t.Parallel()
 
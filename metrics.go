@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ninibe/netlog/metrics"
+)
+
+// topicMetrics counts the traffic of a topic, nil metrics count nothing.
+type topicMetrics struct {
+	written      metrics.Counter
+	writtenBytes metrics.Counter
+	read         metrics.Counter
+	readBytes    metrics.Counter
+	flushTime    *metrics.Histogram
+	flushSize    *metrics.Histogram
+}
+
+func newTopicMetrics() *topicMetrics {
+	return &topicMetrics{
+		flushTime: metrics.NewHistogram(metrics.LatencyBuckets),
+		flushSize: metrics.NewHistogram(metrics.SizeBuckets),
+	}
+}
+
+// wrote counts n messages of size bytes written.
+func (tm *topicMetrics) wrote(n int, size int) {
+	if tm != nil {
+		tm.written.Add(int64(n))
+		tm.writtenBytes.Add(int64(size))
+	}
+}
+
+// readN counts n messages of size bytes read.
+func (tm *topicMetrics) readN(n int, size int64) {
+	if tm != nil {
+		tm.read.Add(int64(n))
+		tm.readBytes.Add(size)
+	}
+}
+
+// flushed records a flush of n buffered messages started at `start`.
+func (tm *topicMetrics) flushed(start time.Time, n int) {
+	if tm != nil {
+		tm.flushTime.Observe(time.Since(start).Seconds())
+		tm.flushSize.Observe(float64(n))
+	}
+}
+
+// CollectMetrics adds the metrics of every topic to mw.
+func (nl *NetLog) CollectMetrics(mw *metrics.Writer) {
+	names := nl.TopicList()
+	sort.Strings(names)
+	for _, name := range names {
+		t, err := nl.Topic(name)
+		if err != nil {
+			continue
+		}
+
+		if err = t.collectMetrics(mw); err != nil {
+			log.Printf("warn: failed to collect metrics of topic %q: %s", name, err)
+		}
+	}
+}
+
+func (t *Topic) collectMetrics(mw *metrics.Writer) error {
+	info, err := t.Info()
+	if err != nil {
+		return err
+	}
+
+	tm, l := t.metrics, []string{"topic", t.name}
+	mw.Counter("netlog_written_messages_total", "Messages written to the topic.", tm.written.Value(), l...)
+	mw.Counter("netlog_written_bytes_total", "Bytes of the messages written to the topic.", tm.writtenBytes.Value(), l...)
+	mw.Counter("netlog_read_messages_total", "Messages read from the topic by scanners, streams and groups.", tm.read.Value(), l...)
+	mw.Counter("netlog_read_bytes_total", "Bytes of the messages read from the topic.", tm.readBytes.Value(), l...)
+	mw.Histogram("netlog_flush_duration_seconds", "Latency of the flushes of batched messages.", tm.flushTime, l...)
+	mw.Histogram("netlog_flush_messages", "Number of messages per flush of batched messages.", tm.flushSize, l...)
+
+	mw.Gauge("netlog_latest_offset", "Latest offset written in the topic.", float64(info.LatestOffset), l...)
+	mw.Gauge("netlog_segments", "Segments of the topic on disk.", float64(len(info.Segments)), l...)
+	mw.Gauge("netlog_disk_bytes", "Disk size of the topic segments.", float64(info.DiskSize), l...)
+	mw.Gauge("netlog_readers", "Active readers of the topic log.", float64(info.Readers), l...)
+	mw.Gauge("netlog_watchers", "Active watchers of the topic log.", float64(info.Watchers), l...)
+
+	for _, si := range t.ScannersInfo() {
+		l := []string{"topic", t.name, "scanner", si.ID}
+		mw.Gauge("netlog_scanner_lag_messages", "Messages left to scan by the scanner.", float64(si.Lag), l...)
+		mw.Gauge("netlog_scanner_lag_bytes", "Bytes left to scan by the scanner.", float64(si.LagBytes), l...)
+		mw.Gauge("netlog_scanner_idle_seconds", "Time since the scanner last returned messages.", si.IdleSeconds, l...)
+		behind := 0.0
+		if si.Behind {
+			behind = 1
+		}
+
+		mw.Gauge("netlog_scanner_behind", "Whether messages were discarded before being scanned by the scanner.", behind, l...)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package metrics implements the few metric types NetLog exposes
+// and writes them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ContentType is the content type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	// LatencyBuckets are the default buckets in seconds of latency histograms.
+	LatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// SizeBuckets are the default buckets of histograms of batch sizes.
+	SizeBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+)
+
+// Counter is a monotonically increasing value safe for concurrent use.
+type Counter struct {
+	v uint64
+}
+
+// Add increments the counter by n.
+func (c *Counter) Add(n int64) {
+	atomic.AddUint64(&c.v, uint64(n))
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	return float64(atomic.LoadUint64(&c.v))
+}
+
+// Histogram counts observations in buckets like Prometheus histograms.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewHistogram returns a histogram with the given upper bounds of
+// its buckets, in increasing order. The +Inf bucket is implicit.
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Observe adds a value to the histogram.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v)
+
+	h.mu.Lock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+
+	h.count++
+	h.sum += v
+	h.mu.Unlock()
+}
+
+// snapshot returns the cumulative counts of the buckets, the total count and the sum.
+func (h *Histogram) snapshot() (cumulative []uint64, count uint64, sum float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cumulative = make([]uint64, len(h.counts))
+	var acc uint64
+	for k, c := range h.counts {
+		acc += c
+		cumulative[k] = acc
+	}
+
+	return cumulative, h.count, h.sum
+}
+
+// HistogramVec is a set of histograms with the same buckets identified by their labels.
+type HistogramVec struct {
+	buckets []float64
+	mu      sync.RWMutex
+	hists   map[string]*Histogram
+	labels  map[string][]string
+}
+
+// NewHistogramVec returns an empty set of histograms with the given buckets.
+func NewHistogramVec(buckets []float64) *HistogramVec {
+	return &HistogramVec{
+		buckets: buckets,
+		hists:   make(map[string]*Histogram),
+		labels:  make(map[string][]string),
+	}
+}
+
+// With returns the histogram of the given label pairs, creating it if needed.
+func (hv *HistogramVec) With(labels ...string) *Histogram {
+	key := strings.Join(labels, "\xff")
+	hv.mu.RLock()
+	h, ok := hv.hists[key]
+	hv.mu.RUnlock()
+	if ok {
+		return h
+	}
+
+	hv.mu.Lock()
+	defer hv.mu.Unlock()
+	if h, ok = hv.hists[key]; !ok {
+		h = NewHistogram(hv.buckets)
+		hv.hists[key] = h
+		hv.labels[key] = labels
+	}
+
+	return h
+}
+
+// Write writes every histogram of the set with the name given.
+func (hv *HistogramVec) Write(mw *Writer, name, help string) {
+	hv.mu.RLock()
+	keys := make([]string, 0, len(hv.hists))
+	for k := range hv.hists {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		mw.Histogram(name, help, hv.hists[k], hv.labels[k]...)
+	}
+
+	hv.mu.RUnlock()
+}
+
+// Writer collects metrics and writes them in the Prometheus text format. Samples of the
+// same metric can be added in any order, they are grouped together when flushed.
+type Writer struct {
+	w        io.Writer
+	families []*family
+	byName   map[string]*family
+}
+
+type family struct {
+	name, help, typ string
+	lines           []string
+}
+
+// NewWriter returns a Writer of metrics into w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, byName: make(map[string]*family)}
+}
+
+// Counter adds a sample of a counter with the given label pairs.
+func (mw *Writer) Counter(name, help string, v float64, labels ...string) {
+	mw.add(name, help, "counter", name, v, labels)
+}
+
+// Gauge adds a sample of a gauge with the given label pairs.
+func (mw *Writer) Gauge(name, help string, v float64, labels ...string) {
+	mw.add(name, help, "gauge", name, v, labels)
+}
+
+// Histogram adds the buckets, count and sum of a histogram with the given label pairs.
+func (mw *Writer) Histogram(name, help string, h *Histogram, labels ...string) {
+	cumulative, count, sum := h.snapshot()
+	for k, le := range h.buckets {
+		mw.add(name, help, "histogram", name+"_bucket", float64(cumulative[k]),
+			append(labels[:len(labels):len(labels)], "le", formatFloat(le)))
+	}
+
+	mw.add(name, help, "histogram", name+"_bucket", float64(count),
+		append(labels[:len(labels):len(labels)], "le", "+Inf"))
+	mw.add(name, help, "histogram", name+"_sum", sum, labels)
+	mw.add(name, help, "histogram", name+"_count", float64(count), labels)
+}
+
+func (mw *Writer) add(name, help, typ, sample string, v float64, labels []string) {
+	f, ok := mw.byName[name]
+	if !ok {
+		f = &family{name: name, help: help, typ: typ}
+		mw.byName[name] = f
+		mw.families = append(mw.families, f)
+	}
+
+	f.lines = append(f.lines, sample+formatLabels(labels)+" "+formatFloat(v))
+}
+
+// Flush writes all the metrics collected.
+func (mw *Writer) Flush() error {
+	bw := bufio.NewWriter(mw.w)
+	for _, f := range mw.families {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ)
+		for _, l := range f.lines {
+			bw.WriteString(l)
+			bw.WriteByte('\n')
+		}
+	}
+
+	mw.families, mw.byName = nil, make(map[string]*family)
+	return bw.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for k := 0; k+1 < len(labels); k += 2 {
+		if k > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(labels[k])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(labels[k+1]))
+		sb.WriteByte('"')
+	}
+
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
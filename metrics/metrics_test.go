@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	h := NewHistogram([]float64{1, 5})
+	for _, v := range []float64{0.5, 1, 3, 10} {
+		h.Observe(v)
+	}
+
+	var c Counter
+	c.Add(2)
+
+	buf := &bytes.Buffer{}
+	mw := NewWriter(buf)
+	mw.Counter("requests_total", "Requests.", c.Value(), "path", "/a")
+	mw.Histogram("size", "Sizes.", h, "path", "/a")
+	mw.Counter("requests_total", "Requests.", 1, "path", `"b"`)
+	if err := mw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{path="/a"} 2
+requests_total{path="\"b\""} 1
+# HELP size Sizes.
+# TYPE size histogram
+size_bucket{path="/a",le="1"} 2
+size_bucket{path="/a",le="5"} 3
+size_bucket{path="/a",le="+Inf"} 4
+size_sum{path="/a"} 14.5
+size_count{path="/a"} 4
+`
+
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", buf, expected)
+	}
+}
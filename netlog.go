@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/ninibe/bigduration"
@@ -26,6 +27,14 @@ type NetLog struct {
 	topics        *TopicAtomicMap
 	topicSettings TopicSettings
 	monInterval   bigduration.BigDuration
+	readOnly      int32
+	maxBytes      int64
+	quotaPolicy   QuotaPolicy
+	overQuota     int32
+	archive       biglog.Archive
+	scannerTTL    bigduration.BigDuration
+	closed        int32
+	monitor       *SegmentMonitor
 }
 
 // DefaultTopicSettings sets the default topic settings used if no other is defined at creation time.
@@ -42,6 +51,30 @@ func MonitorInterval(interval bigduration.BigDuration) Option {
 	}
 }
 
+// ArchiveSegments uploads the segments of every topic to the archive before discarding
+// them, scanners and readers of archived offsets fetch them back transparently.
+func ArchiveSegments(a biglog.Archive) Option {
+	return func(nl *NetLog) {
+		nl.archive = a
+	}
+}
+
+// ScannerTTL sets the time after which idle non-persistent scanners are closed
+// and removed by the segment monitor, scanning renews it. Zero means never.
+func ScannerTTL(ttl bigduration.BigDuration) Option {
+	return func(nl *NetLog) {
+		nl.scannerTTL = ttl
+	}
+}
+
+// ReadOnly starts the NetLog as a read-only follower, only topics
+// replicated from a leader can be created and written into.
+func ReadOnly() Option {
+	return func(bl *NetLog) {
+		bl.readOnly = 1
+	}
+}
+
 // NewNetLog creates a new NetLog in a given data folder that must exist and be writable.
 func NewNetLog(dataDir string, opts ...Option) (nl *NetLog, err error) {
 	d, err := os.Stat(dataDir)
@@ -71,8 +104,8 @@ func NewNetLog(dataDir string, opts ...Option) (nl *NetLog, err error) {
 		mi = time.Second
 	}
 
-	sm := &SegmentMonitor{nl: nl}
-	go sm.start(mi)
+	nl.monitor = newSegmentMonitor(nl)
+	go nl.monitor.start(mi)
 
 	return nl, err
 }
@@ -114,6 +147,10 @@ func (nl *NetLog) loadTopic(name string) (err error) {
 		return err
 	}
 
+	if err = nl.setArchive(bl); err != nil {
+		return err
+	}
+
 	settingsPath := filepath.Join(topicPath, settingsFile)
 	f, err := os.OpenFile(settingsPath, os.O_RDWR, 0666)
 	if err != nil {
@@ -127,12 +164,75 @@ func (nl *NetLog) loadTopic(name string) (err error) {
 		return err
 	}
 
-	t := newTopic(bl, settings, nl.topicSettings)
+	t := newTopic(nl, bl, settings)
 	return nl.register(name, t)
 }
 
+func (nl *NetLog) setArchive(bl *biglog.BigLog) error {
+	if nl.archive == nil {
+		return nil
+	}
+
+	return bl.SetArchive(nl.archive)
+}
+
+// SetReadOnly switches the NetLog from and to read-only mode.
+// Read-only NetLogs reject any write with ErrReadOnly.
+func (nl *NetLog) SetReadOnly(readOnly bool) {
+	var ro int32
+	if readOnly {
+		ro = 1
+	}
+
+	atomic.StoreInt32(&nl.readOnly, ro)
+}
+
+// IsReadOnly returns whether the NetLog rejects writes.
+func (nl *NetLog) IsReadOnly() bool {
+	return atomic.LoadInt32(&nl.readOnly) == 1
+}
+
+func (nl *NetLog) isClosed() bool {
+	return atomic.LoadInt32(&nl.closed) == 1
+}
+
+// writable returns the error writes get while the NetLog is closed or read-only.
+func (nl *NetLog) writable() error {
+	if nl.isClosed() {
+		return ErrClosed
+	}
+
+	if nl.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	return nil
+}
+
 // CreateTopic creates a new topic with a given name and default settings.
 func (nl *NetLog) CreateTopic(name string, settings TopicSettings) (t *Topic, err error) {
+	if err = nl.writable(); err != nil {
+		return nil, err
+	}
+
+	return nl.createTopic(name, settings, 0)
+}
+
+// CreateReplica creates a new topic replicating one of a leader, where the
+// first offset written is `first`. Replicas can be created on read-only NetLogs.
+func (nl *NetLog) CreateReplica(name string, settings TopicSettings, first int64) (t *Topic, err error) {
+	if first < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	if nl.isClosed() {
+		return nil, ErrClosed
+	}
+
+	return nl.createTopic(name, settings, first)
+}
+
+func (nl *NetLog) createTopic(name string, settings TopicSettings, first int64) (t *Topic, err error) {
 	defer func() {
 		if err != nil {
 			log.Printf("warn: failed to create topic %q: %s", name, err)
@@ -143,30 +243,27 @@ func (nl *NetLog) CreateTopic(name string, settings TopicSettings) (t *Topic, er
 		return t, ErrTopicExists
 	}
 
-	topicPath := filepath.Join(nl.dataDir, name)
-	bl, err := biglog.Create(topicPath, 100*1024)
-	if err != nil {
+	if err = validateSettings(settings); err != nil {
 		return nil, err
 	}
 
-	t = newTopic(bl, settings, nl.topicSettings)
-	err = nl.register(name, t)
+	topicPath := filepath.Join(nl.dataDir, name)
+	bl, err := biglog.CreateAt(topicPath, 100*1024, first)
 	if err != nil {
 		return nil, err
 	}
 
-	settingsPath := filepath.Join(topicPath, settingsFile)
-	f, err := os.OpenFile(settingsPath, os.O_RDWR|os.O_CREATE, 0666)
-	if err != nil {
+	if err = nl.setArchive(bl); err != nil {
 		return nil, err
 	}
 
-	enc := json.NewEncoder(f)
-	err = enc.Encode(t.settings)
+	t = newTopic(nl, bl, settings)
+	err = nl.register(name, t)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
+	err = writeSettings(filepath.Join(topicPath, settingsFile), t.settings)
 	return t, err
 }
 
@@ -187,6 +284,10 @@ func (nl *NetLog) DeleteTopic(name string, force bool) (err error) {
 		}
 	}()
 
+	if err = nl.writable(); err != nil {
+		return err
+	}
+
 	log.Printf("info: deleting topic %q force=%t", name, force)
 	t, err := nl.Topic(name)
 	if err != nil {
@@ -0,0 +1,158 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// QuotaPolicy defines what happens when a size limit is reached.
+type QuotaPolicy string
+
+const (
+	// QuotaTrim discards the oldest segments until the size is within the limit.
+	QuotaTrim QuotaPolicy = "trim"
+	// QuotaReject rejects writes with ErrQuotaExceeded until the size is within the limit.
+	QuotaReject QuotaPolicy = "reject"
+)
+
+// ParseQuotaPolicy parses a quota policy, empty strings
+// are valid and mean the default policy of the server.
+func ParseQuotaPolicy(s string) (QuotaPolicy, error) {
+	p := QuotaPolicy(s)
+	switch p {
+	case "", QuotaTrim, QuotaReject:
+		return p, nil
+	}
+
+	return "", ErrInvalidQuotaPolicy
+}
+
+// DataQuota limits the disk size of all topics together. Sizes are checked
+// by the segment monitor, so the limit can be exceeded in between checks.
+func DataQuota(maxBytes int64, policy QuotaPolicy) Option {
+	return func(nl *NetLog) {
+		nl.maxBytes = maxBytes
+		nl.quotaPolicy = policy
+	}
+}
+
+// checkQuota returns ErrQuotaExceeded if the topic or the server are over
+// their size limits with the reject policy.
+func (t *Topic) checkQuota() error {
+	if atomic.LoadInt32(&t.overQuota) == 1 ||
+		atomic.LoadInt32(&t.nl.overQuota) == 1 {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// Check that the topic is within its size limit.
+func (t *Topic) checkTopicSize() error {
+	var over int32
+	defer func() { atomic.StoreInt32(&t.overQuota, over) }()
+
+	settings := t.Settings()
+	if settings.MaxBytes <= 0 {
+		return nil
+	}
+
+	// segments may have been discarded or split already
+	bi, err := t.bl.Info()
+	if err != nil {
+		return err
+	}
+
+	if bi.DiskSize <= settings.MaxBytes {
+		return nil
+	}
+
+	if settings.QuotaPolicy == QuotaReject {
+		over = 1
+		return nil
+	}
+
+	size := bi.DiskSize
+	for _, seg := range bi.Segments[:len(bi.Segments)-1] {
+		if size <= settings.MaxBytes {
+			break
+		}
+
+		log.Printf("info: removing segment over size limit on %q", t.name)
+		if err := t.bl.Trim(); err != nil {
+			return err
+		}
+
+		size -= seg.DiskSize
+	}
+
+	return nil
+}
+
+// checkDataQuota checks that all topics together are within the server size limit,
+// trimming the oldest segment among all topics at a time under the trim policy.
+func (nl *NetLog) checkDataQuota() error {
+	var over int32
+	defer func() { atomic.StoreInt32(&nl.overQuota, over) }()
+
+	if nl.maxBytes <= 0 {
+		return nil
+	}
+
+	infos := make(map[*Topic]*biglog.Info)
+	var size int64
+	for _, t := range nl.topics.GetAll() {
+		bi, err := t.bl.Info()
+		if err != nil {
+			return err
+		}
+
+		infos[t] = bi
+		size += bi.DiskSize
+	}
+
+	if size <= nl.maxBytes {
+		return nil
+	}
+
+	if nl.quotaPolicy == QuotaReject {
+		over = 1
+		return nil
+	}
+
+	for size > nl.maxBytes {
+		// topic with the oldest closed segment
+		var oldest *Topic
+		for t, bi := range infos {
+			if len(bi.Segments) < 2 {
+				continue
+			}
+
+			if oldest == nil || bi.Segments[0].ModTime.Before(infos[oldest].Segments[0].ModTime) {
+				oldest = t
+			}
+		}
+
+		if oldest == nil {
+			log.Printf("warn: data quota exceeded without segments to remove")
+			return nil
+		}
+
+		log.Printf("info: removing segment over data quota on %q", oldest.name)
+		if err := oldest.bl.Trim(); err != nil {
+			return err
+		}
+
+		bi := infos[oldest]
+		size -= bi.Segments[0].DiskSize
+		bi.Segments = bi.Segments[1:]
+	}
+
+	return nil
+}
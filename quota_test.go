@@ -0,0 +1,123 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"testing"
+)
+
+func TestTopicQuota(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	if _, err := nl.CreateTopic(randStr(6), TopicSettings{QuotaPolicy: "drop"}); err != ErrInvalidQuotaPolicy {
+		t.Errorf("Expected ErrInvalidQuotaPolicy, got %v", err)
+	}
+
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = topic.WriteMessages(randMessageSet())
+		panicOn(err)
+		panicOn(topic.bl.Split())
+	}
+
+	segments := func() int {
+		bi, err2 := topic.bl.Info()
+		panicOn(err2)
+		return len(bi.Segments)
+	}
+
+	bi, err := topic.bl.Info()
+	panicOn(err)
+
+	// writes are rejected until the topic is back within its limit
+	topic.settings.MaxBytes = bi.DiskSize - 1
+	topic.settings.QuotaPolicy = QuotaReject
+	panicOn(topic.CheckSegments())
+	if _, err = topic.WriteMessages(randMessageSet()); err != ErrQuotaExceeded {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+
+	if n := segments(); n != 4 {
+		t.Errorf("Expected 4 segments, got %d", n)
+	}
+
+	topic.settings.MaxBytes = bi.DiskSize
+	panicOn(topic.CheckSegments())
+	_, err = topic.WriteMessages(randMessageSet())
+	panicOn(err)
+
+	// only the oldest segments needed are discarded
+	topic.settings.MaxBytes = bi.DiskSize - 1
+	topic.settings.QuotaPolicy = QuotaTrim
+	panicOn(topic.CheckSegments())
+	if n := segments(); n != 3 {
+		t.Errorf("Expected 3 segments, got %d", n)
+	}
+
+	if topic.bl.Oldest() == 0 {
+		t.Error("Oldest segment not discarded")
+	}
+
+	// the hot segment is never discarded
+	topic.settings.MaxBytes = 1
+	panicOn(topic.CheckSegments())
+	if n := segments(); n != 1 {
+		t.Errorf("Expected 1 segment, got %d", n)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
+
+func TestDataQuota(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	var topics []*Topic
+	for i := 0; i < 2; i++ {
+		topic, err := nl.CreateTopic(randStr(6), TopicSettings{})
+		panicOn(err)
+
+		_, err = topic.WriteMessages(randMessageSet())
+		panicOn(err)
+		panicOn(topic.bl.Split())
+		topics = append(topics, topic)
+	}
+
+	var size int64
+	for _, topic := range topics {
+		bi, err := topic.bl.Info()
+		panicOn(err)
+		size += bi.DiskSize
+	}
+
+	nl.maxBytes, nl.quotaPolicy = size-1, QuotaReject
+	panicOn(nl.checkDataQuota())
+	if _, err := topics[1].WriteMessages(randMessageSet()); err != ErrQuotaExceeded {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// the oldest segment among all topics goes first
+	nl.quotaPolicy = QuotaTrim
+	panicOn(nl.checkDataQuota())
+	for k, expected := range []int{1, 2} {
+		bi, err := topics[k].bl.Info()
+		panicOn(err)
+		if len(bi.Segments) != expected {
+			t.Errorf("Expected %d segments in topic %d, got %d", expected, k, len(bi.Segments))
+		}
+	}
+
+	if _, err := topics[1].WriteMessages(randMessageSet()); err != nil {
+		t.Errorf("Write rejected after trimming: %v", err)
+	}
+
+	for _, topic := range topics {
+		panicOn(nl.DeleteTopic(topic.name, true))
+	}
+}
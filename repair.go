@@ -0,0 +1,78 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"io"
+	"log"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// Repair checks every segment of the topic against its data, rebuilding indexes and
+// truncating corrupted data, see RepairLog. Writes to the topic wait during the repair.
+func (t *Topic) Repair(quarantine bool) ([]*biglog.RepairReport, error) {
+	if err := t.FlushBuffered(); err != nil {
+		return nil, err
+	}
+
+	return RepairLog(t.bl, quarantine)
+}
+
+// RepairLog repairs every segment of a topic log with biglog.RepairSegment, walking the stored
+// messages, and returns a report per segment. Bad data is moved to the quarantine folder of the
+// log if quarantine is set. Segments that can not be repaired, for instance because they are
+// being read, have the error set in their reports.
+func RepairLog(bl *biglog.BigLog, quarantine bool) ([]*biglog.RepairReport, error) {
+	info, err := bl.Info()
+	if err != nil {
+		return nil, ExtErr(err)
+	}
+
+	var reports []*biglog.RepairReport
+	for _, si := range info.Segments {
+		rep, err := bl.RepairSegment(si.FirstOffset, parseEntry, quarantine)
+		if err != nil {
+			log.Printf("error: failed to repair segment %d of %s: %s", si.FirstOffset, info.Name, err)
+			rep = &biglog.RepairReport{Segment: si.FirstOffset, Error: err.Error()}
+		}
+
+		if rep.Repaired {
+			log.Printf("warn: repaired segment %d of %s: %d entries rebuilt, %d bytes truncated, %d offsets lost",
+				rep.Segment, info.Name, rep.Rebuilt, rep.Truncated, rep.Lost)
+		}
+
+		reports = append(reports, rep)
+	}
+
+	return reports, nil
+}
+
+// parseEntry is the biglog.EntryParser of stored messages, which
+// are valid if they fit in the data left and pass the integrity check.
+func parseEntry(r *io.SectionReader) (int64, int, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, ErrCorruptMessage
+	}
+
+	// a corrupted length must not allocate more than the data left
+	if m := Message(header); int64(m.Size()) > r.Size() {
+		return 0, 0, ErrCorruptMessage
+	}
+
+	_, _ = r.Seek(0, io.SeekStart)
+	entry, err := ReadMessage(r)
+	if err != nil || CheckMessageIntegrity(entry, 1) != nil {
+		return 0, 0, ErrCorruptMessage
+	}
+
+	n, err := EntryOffsets(entry)
+	if err != nil || n < 1 {
+		return 0, 0, ErrCorruptMessage
+	}
+
+	return int64(len(entry)), n, nil
+}
@@ -0,0 +1,108 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{CompressionType: CompressionNone})
+	panicOn(err)
+
+	var payloads [][]byte
+	for i := 0; i < 6; i++ {
+		payloads = append(payloads, randData(10))
+		_, err = topic.WriteMessage(MessageFromPayload(payloads[i]))
+		panicOn(err)
+		if i == 2 {
+			panicOn(topic.bl.Split())
+		}
+	}
+
+	panicOn(topic.Sync())
+	segPath := func(base int64, ext string) string {
+		return filepath.Join(nl.dataDir, topicName, fmt.Sprintf("%020d.%s", base, ext))
+	}
+
+	// corrupt the payload of offset 1 in the closed segment
+	f, err := os.OpenFile(segPath(0, "data"), os.O_RDWR, 0666)
+	panicOn(err)
+	entrySize := int64(len(MessageFromPayload(payloads[0])))
+	_, err = f.WriteAt([]byte("corrupted"), 16+entrySize+headerSize)
+	panicOn(err)
+	panicOn(f.Close())
+
+	// lose the index entries of the hot segment and leave garbage at the end of its data file as a partial write
+	f, err = os.OpenFile(segPath(3, "index"), os.O_RDWR, 0666)
+	panicOn(err)
+	_, err = f.WriteAt(make([]byte, 100), 0)
+	panicOn(err)
+	panicOn(f.Close())
+
+	f, err = os.OpenFile(segPath(3, "data"), os.O_WRONLY|os.O_APPEND, 0666)
+	panicOn(err)
+	_, err = f.Write([]byte("partial write"))
+	panicOn(err)
+	panicOn(f.Close())
+
+	reports, err := topic.Repair(true)
+	panicOn(err)
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 reports, got %d", len(reports))
+	}
+
+	closed, hot := reports[0], reports[1]
+	if !closed.Repaired || closed.Lost != 1 || len(closed.Quarantined) != 1 {
+		t.Errorf("Unexpected report of the closed segment %+v", closed)
+	} else if _, err = os.Stat(closed.Quarantined[0].Path); err != nil {
+		t.Errorf("Quarantined data not found: %s", err)
+	}
+
+	if !hot.Repaired || hot.Rebuilt != 3 || hot.Truncated != int64(len("partial write")) || hot.Lost != 0 {
+		t.Errorf("Unexpected report of the hot segment %+v", hot)
+	}
+
+	for o, p := range payloads {
+		actual, err2 := topic.Payload(int64(o))
+		if o == 1 {
+			if err2 != ErrOffsetNotFound {
+				t.Errorf("Expected ErrOffsetNotFound for the quarantined offset, got %v", err2)
+			}
+
+			continue
+		}
+
+		panicOn(err2)
+		if !bytes.Equal(actual, p) {
+			t.Errorf("Unexpected payload at offset %d", o)
+		}
+	}
+
+	// writes go on after the rebuilt entries
+	o, err := topic.WriteMessage(MessageFromPayload(randData(10)))
+	panicOn(err)
+	if o != 6 {
+		t.Errorf("Expected offset 6, got %d", o)
+	}
+
+	reports, err = topic.Repair(false)
+	panicOn(err)
+	for _, rep := range reports {
+		if rep.Repaired {
+			t.Errorf("Healthy segment %d repaired: %+v", rep.Segment, rep)
+		}
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
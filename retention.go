@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// Check that the oldest segment is not too old. With scanner retention the
+// segment is kept while persistent scanners have not scanned past it, unless
+// it is older than the max segment age.
+func (t *Topic) checkSegmentsAge(bi *biglog.Info) error {
+	settings := t.Settings()
+	if settings.SegAge.Duration() <= 0 {
+		return nil
+	}
+
+	if len(bi.Segments) < 2 {
+		return nil
+	}
+
+	modTime := bi.Segments[0].ModTime
+	if settings.SegAge.From(modTime).After(time.Now()) {
+		return nil
+	}
+
+	lagging := t.laggingScanners(bi.Segments[1].FirstOffset)
+	if len(lagging) == 0 {
+		log.Printf("info: removing old segment on %q", t.name)
+		return t.bl.Trim()
+	}
+
+	expired := settings.MaxSegAge.Duration() > 0 &&
+		!settings.MaxSegAge.From(modTime).After(time.Now())
+
+	if settings.ScannerRetention && !expired {
+		log.Printf("debug: keeping old segment on %q for scanners %v", t.name, lagging)
+		return nil
+	}
+
+	log.Printf("warn: removing old segment on %q, scanners %v skip forward", t.name, lagging)
+	return t.bl.Trim()
+}
+
+// retentionEdge returns the first offset not due to be discarded by age.
+func retentionEdge(bi *biglog.Info, settings TopicSettings) int64 {
+	edge := bi.FirstOffset
+	if settings.SegAge.Duration() <= 0 || settings.CleanupPolicy == CleanupCompact {
+		return edge
+	}
+
+	now := time.Now()
+	// the last segment is still being written
+	for k, seg := range bi.Segments[:len(bi.Segments)-1] {
+		if settings.SegAge.From(seg.ModTime).After(now) {
+			break
+		}
+
+		edge = bi.Segments[k+1].FirstOffset
+	}
+
+	return edge
+}
+
+// laggingScanners returns the IDs of the persistent scanners that
+// would resume scanning before offset `edge` after a restart.
+func (t *Topic) laggingScanners(edge int64) []string {
+	var lagging []string
+	for ID, ts := range t.scanners.GetAll() {
+		if next, ok := resumeOffset(ts); ok && next < edge {
+			lagging = append(lagging, ID)
+		}
+	}
+
+	sort.Strings(lagging)
+	return lagging
+}
+
+// resumeOffset returns the offset a persistent scanner resumes from after a restart,
+// ok is false for scanners that do not survive restarts.
+func resumeOffset(ts TopicScanner) (next int64, ok bool) {
+	switch s := ts.(type) {
+	case *AckTopicScanner:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.acked + 1, true
+	case *PersistentTopicScanner:
+		return atomic.LoadInt64(&s.last) + 1, true
+	}
+
+	return 0, false
+}
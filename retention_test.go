@@ -0,0 +1,165 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ninibe/bigduration"
+)
+
+func TestScannerRetention(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	segAge, err := bigduration.ParseBigDuration("1ms")
+	panicOn(err)
+
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		SegAge:           segAge,
+		ScannerRetention: true,
+	})
+	panicOn(err)
+
+	write := func() {
+		_, err2 := topic.WriteMessage(MessageFromPayload(randData(32)))
+		panicOn(err2)
+	}
+
+	segments := func() int {
+		bi, err2 := topic.bl.Info()
+		panicOn(err2)
+		return len(bi.Segments)
+	}
+
+	lagging := func() []string {
+		info, err2 := topic.Info()
+		panicOn(err2)
+		return info.Lagging
+	}
+
+	write()
+	ps, err := topic.NewScanner(0, true)
+	panicOn(err)
+
+	panicOn(topic.bl.Split())
+	write()
+	time.Sleep(5 * time.Millisecond)
+
+	if l := lagging(); !reflect.DeepEqual(l, []string{ps.ID()}) {
+		t.Errorf("Expected lagging scanner %s, got %v", ps.ID(), l)
+	}
+
+	panicOn(topic.CheckSegments())
+	if n := segments(); n != 2 {
+		t.Errorf("Old segment discarded with a scanner in it, %d segments left", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// move the reader into the next segment
+	for i := 0; i < 2; i++ {
+		_, _, err = ps.Scan(ctx)
+		panicOn(err)
+	}
+
+	// a scan waiting for messages doesn't hold the retention back
+	wctx, wcancel := context.WithCancel(ctx)
+	waiting := make(chan error)
+	go func() {
+		_, _, err2 := ps.Scan(wctx)
+		waiting <- err2
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if l := lagging(); len(l) > 0 {
+		t.Errorf("Unexpected lagging scanners %v", l)
+	}
+
+	wcancel()
+	<-waiting
+
+	panicOn(topic.CheckSegments())
+	if n := segments(); n != 1 {
+		t.Errorf("Old segment kept after being scanned, %d segments left", n)
+	}
+
+	// un-acked messages are scanned again from the old segment after a restart
+	panicOn(topic.DeleteScanner(ps.ID()))
+	as, err := topic.NewAckScanner(1, time.Minute)
+	panicOn(err)
+
+	write()
+	panicOn(topic.bl.Split())
+	write()
+	for i := 0; i < 3; i++ {
+		_, _, err = as.Scan(ctx)
+		panicOn(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	panicOn(topic.CheckSegments())
+	if l := lagging(); !reflect.DeepEqual(l, []string{as.ID()}) || segments() != 2 {
+		t.Errorf("Expected ack scanner %s lagging in 2 segments, got %v in %d", as.ID(), l, segments())
+	}
+
+	// the max segment age bounds the retention
+	panicOn(topic.UpdateSettings(TopicSettings{SegAge: segAge, ScannerRetention: true, MaxSegAge: segAge}))
+	panicOn(topic.CheckSegments())
+	if n := segments(); n != 1 {
+		t.Errorf("Segment older than max age kept, %d segments left", n)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
+
+func TestScannerRetentionUnused(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	segAge, err := bigduration.ParseBigDuration("1ms")
+	panicOn(err)
+
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		SegAge:           segAge,
+		ScannerRetention: true,
+	})
+	panicOn(err)
+
+	_, err = topic.WriteMessage(MessageFromPayload(randData(32)))
+	panicOn(err)
+	panicOn(topic.bl.Split())
+	_, err = topic.WriteMessage(MessageFromPayload(randData(32)))
+	panicOn(err)
+
+	// a scanner that never scanned resumes where it was created
+	ps, err := topic.NewScanner(1, true)
+	panicOn(err)
+
+	if last, _ := scannerFromFile(topic.scannerPath(ps.ID())); last != 0 {
+		t.Errorf("New scanner persisted at %d instead of %d", last, 0)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	info, err := topic.Info()
+	panicOn(err)
+	if len(info.Lagging) > 0 {
+		t.Errorf("Unexpected lagging scanners %v", info.Lagging)
+	}
+
+	panicOn(topic.CheckSegments())
+	bi, err := topic.bl.Info()
+	panicOn(err)
+	if len(bi.Segments) != 1 {
+		t.Errorf("Old segment kept by a scanner after it, %d segments left", len(bi.Segments))
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
@@ -5,23 +5,58 @@
 package netlog
 
 import (
+	"context"
 	"log"
 	"time"
 )
 
-// SegmentMonitor periodically checks for segments
-// to split or discard at a given interval.
+// SegmentMonitor periodically checks for segments to split or
+// discard and for idle scanners to expire at a given interval.
 type SegmentMonitor struct {
-	nl *NetLog
+	nl   *NetLog
+	quit chan struct{}
+	done chan struct{}
+}
+
+func newSegmentMonitor(nl *NetLog) *SegmentMonitor {
+	return &SegmentMonitor{
+		nl:   nl,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
 }
 
 func (sm *SegmentMonitor) start(interval time.Duration) {
+	defer close(sm.done)
+
 	ticker := time.NewTicker(interval)
-	for range ticker.C {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sm.quit:
+			return
+		case <-ticker.C:
+		}
+
 		log.Printf("trace: running segment monitor")
 		for name, t := range sm.nl.topics.GetAll() {
 			sm.check(name, t)
 		}
+
+		if err := sm.nl.checkDataQuota(); err != nil {
+			log.Printf("error: check data quota failed %s", err)
+		}
+	}
+}
+
+// stop stops the monitor and waits for a running check to finish or ctx to be done.
+func (sm *SegmentMonitor) stop(ctx context.Context) error {
+	close(sm.quit)
+	select {
+	case <-sm.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -41,4 +76,6 @@ func (sm *SegmentMonitor) check(name string, t *Topic) {
 	if err != nil {
 		log.Printf("error: check segments failed %s", err)
 	}
+
+	t.expireScanners()
 }
@@ -0,0 +1,129 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+// closeRetryInterval is the interval at which closing a log still being read is retried.
+const closeRetryInterval = 10 * time.Millisecond
+
+// Close shuts the NetLog down. Writes are rejected with ErrClosed from then on, the segment
+// monitor is stopped and every topic flushes its buffered messages, persists the offsets of
+// its scanners and closes its log, which is synced to disk first. Streams are closed by their
+// readers, logs still being read are retried until ctx is done, then ErrBusy is returned once
+// the rest of the topics are closed.
+// Persistent scanners and consumer groups are restored when the NetLog is opened again.
+func (nl *NetLog) Close(ctx context.Context) (err error) {
+	if !atomic.CompareAndSwapInt32(&nl.closed, 0, 1) {
+		return ErrClosed
+	}
+
+	log.Printf("info: closing netlog on %q", nl.dataDir)
+	if err = nl.monitor.stop(ctx); err != nil {
+		log.Printf("warn: segment monitor still running: %s", err)
+	}
+
+	err = nil
+	for name, t := range nl.topics.GetAll() {
+		if terr := t.close(ctx); terr != nil {
+			log.Printf("error: failed to close topic %q: %s", name, terr)
+			if err == nil {
+				err = terr
+			}
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("info: closed netlog on %q", nl.dataDir)
+	return nil
+}
+
+// close flushes the buffered messages of the topic, releases its scanners and groups and closes its log.
+func (t *Topic) close(ctx context.Context) error {
+	// nothing is appended while the lock is held, writes
+	// waiting for it get ErrClosed from the new writer
+	t.mu.Lock()
+	var err error
+	if mb, ok := t.writer.(*messageBuffer); ok {
+		err = mb.Close()
+	}
+
+	t.writer = closedWriter{latest: t.writer.Latest()}
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for ID, ts := range t.scanners.GetAll() {
+		// scanners held by clients fail from now on
+		t.scanners.Delete(ID)
+		switch s := ts.(type) {
+		case *PersistentTopicScanner:
+			err = s.release()
+		case *AckTopicScanner:
+			err = s.release()
+		default:
+			err = ts.Close()
+		}
+
+		if err != nil {
+			log.Printf("warn: failed to close scanner %s of %q: %s", ID, t.name, err)
+		}
+	}
+
+	for name, g := range t.groups.GetAll() {
+		if err = g.Close(); err != nil {
+			log.Printf("warn: failed to close group %q of %q: %s", name, t.name, err)
+		}
+	}
+
+	if err = t.bl.Sync(); err != nil {
+		return err
+	}
+
+	return closeLog(ctx, t.bl)
+}
+
+// closedWriter is the writer of closed topics, which rejects every append.
+type closedWriter struct {
+	latest int64
+}
+
+func (cw closedWriter) Append(p []byte, n int) (int64, error) {
+	return -1, ErrClosed
+}
+
+func (cw closedWriter) Latest() int64 {
+	return cw.latest
+}
+
+// closeLog closes bl retrying while it has readers until ctx is done.
+func closeLog(ctx context.Context, bl *biglog.BigLog) error {
+	for {
+		switch err := bl.Close(); err {
+		case nil:
+			return nil
+		case biglog.ErrBusy:
+		default:
+			return ExtErr(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrBusy
+		case <-time.After(closeRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog/biglog"
+)
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	longTime, err := bigduration.ParseBigDuration("1day")
+	panicOn(err)
+
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{BatchNumMessages: 100, BatchInterval: longTime})
+	panicOn(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = topic.WriteMessage(MessageFromPayload(randData(10)))
+		panicOn(err)
+	}
+
+	panicOn(topic.FlushBuffered())
+	ts, err := topic.NewScanner(0, true)
+	panicOn(err)
+	_, err = topic.NewScanner(0, false)
+	panicOn(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err = ts.ScanN(ctx, 2, 0)
+	panicOn(err)
+
+	// left in the buffer
+	for i := 0; i < 2; i++ {
+		_, err = topic.WriteMessage(MessageFromPayload(randData(10)))
+		panicOn(err)
+	}
+
+	panicOn(nl.Close(ctx))
+	if _, err = topic.WriteMessage(MessageFromPayload(randData(10))); err != ErrClosed {
+		t.Errorf("Expected ErrClosed writing after close, got %v", err)
+	}
+
+	if err = topic.UpdateSettings(TopicSettings{BatchNumMessages: 5}); err != ErrClosed {
+		t.Errorf("Expected ErrClosed updating settings after close, got %v", err)
+	}
+
+	// writes that passed the check before closing reach the writer
+	if _, err = topic.writer.Append(MessageFromPayload(randData(10)), 1); err != ErrClosed {
+		t.Errorf("Expected the writer to reject appends after close, got %v", err)
+	}
+
+	if err = nl.Close(ctx); err != ErrClosed {
+		t.Errorf("Expected ErrClosed closing twice, got %v", err)
+	}
+
+	if _, _, err = ts.ScanN(ctx, 1, 0); err != ErrClosed {
+		t.Errorf("Expected ErrClosed scanning after close, got %v", err)
+	}
+
+	if _, err = topic.Scanner(ts.ID()); err != ErrScannerNotFound {
+		t.Errorf("Expected scanners removed on close, got %v", err)
+	}
+
+	nl, err = NewNetLog(nl.dataDir, MonitorInterval(longTime))
+	panicOn(err)
+	topic, err = nl.Topic(topicName)
+	panicOn(err)
+	if latest := topic.Latest(); latest != 4 {
+		t.Errorf("Expected buffered messages flushed on close, latest offset %d", latest)
+	}
+
+	infos := topic.ScannersInfo()
+	if len(infos) != 1 || infos[0].ID != ts.ID() || infos[0].Next != 2 {
+		t.Errorf("Expected only the persistent scanner restored at offset 2, got %+v", infos)
+	}
+
+	// logs being read are waited for until the context is done
+	r, _, err := biglog.NewReader(topic.bl, 0)
+	panicOn(err)
+	sctx, scancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer scancel()
+	if err = nl.Close(sctx); err != ErrBusy {
+		t.Errorf("Expected ErrBusy closing a log being read, got %v", err)
+	}
+
+	panicOn(r.Close())
+	panicOn(topic.bl.Close())
+}
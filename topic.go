@@ -13,8 +13,10 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/comail/go-uuid/uuid"
@@ -28,15 +30,23 @@ var enc = binary.BigEndian
 
 //go:generate atomicmapper -pointer -type Topic
 //go:generate atomicmapper -type TopicScanner
+//go:generate atomicmapper -pointer -type ConsumerGroup
 
 // Topic is a log of linear messages.
 type Topic struct {
+	nl        *NetLog
 	name      string
+	mu        sync.RWMutex // guards settings and writer
 	settings  TopicSettings
 	bl        *biglog.BigLog
-	writer    io.Writer
+	writer    appender
 	scanners  *TopicScannerAtomicMap
 	streamers *StreamerAtomicMap
+	groups    *ConsumerGroupAtomicMap
+	syncer    *groupSyncer
+	compactor compactor
+	overQuota int32
+	metrics   *topicMetrics
 }
 
 // TopicSettings holds the tunable settings of a topic.
@@ -51,10 +61,45 @@ type TopicSettings struct {
 	BatchInterval bigduration.BigDuration `json:"batch_interval,ommitempty"`
 	// CompressionType allows to specify how batches are compressed.
 	CompressionType CompressionType `json:"compression_type,ommitempty"`
+	// Durability defines when writes are acknowledged, buffered by default.
+	Durability Durability `json:"durability,omitempty"`
+	// CleanupPolicy defines how old data is discarded, by segment age by default.
+	CleanupPolicy CleanupPolicy `json:"cleanup_policy,omitempty"`
+	// TombstoneRetention is how long tombstones are kept on compacted topics.
+	TombstoneRetention bigduration.BigDuration `json:"tombstone_retention,omitempty"`
+	// MaxBytes is the disk size limit of the topic, unlimited if zero.
+	MaxBytes int64 `json:"max_topic_bytes,omitempty"`
+	// QuotaPolicy defines what happens when MaxBytes is exceeded, trimming old segments by default.
+	QuotaPolicy QuotaPolicy `json:"quota_policy,omitempty"`
+	// ScannerRetention keeps segments older than SegAge while persistent scanners have not scanned them.
+	ScannerRetention bool `json:"scanner_retention,omitempty"`
+	// MaxSegAge is the age after which segments are discarded regardless of scanners, unlimited if zero.
+	MaxSegAge bigduration.BigDuration `json:"max_segment_age,omitempty"`
 }
 
-func newTopic(bl *biglog.BigLog, settings TopicSettings, defaultSettings TopicSettings) *Topic {
+func newTopic(nl *NetLog, bl *biglog.BigLog, settings TopicSettings) *Topic {
+	settings = withDefaults(settings, nl.topicSettings)
+	tm := newTopicMetrics()
+	t := &Topic{
+		nl:        nl,
+		settings:  settings,
+		name:      bl.Name(),
+		bl:        bl,
+		writer:    newWriter(bl, settings, tm),
+		scanners:  NewTopicScannerAtomicMap(),
+		streamers: NewStreamerAtomicMap(),
+		groups:    NewConsumerGroupAtomicMap(),
+		metrics:   tm,
+	}
 
+	t.syncer = newGroupSyncer(bl.Sync, bl.Latest)
+
+	t.restorePersistedReaders()
+	return t
+}
+
+// withDefaults fills the settings left empty with the default ones.
+func withDefaults(settings, defaultSettings TopicSettings) TopicSettings {
 	if settings.SegSize == 0 {
 		settings.SegSize = defaultSettings.SegSize
 	}
@@ -75,32 +120,162 @@ func newTopic(bl *biglog.BigLog, settings TopicSettings, defaultSettings TopicSe
 		settings.CompressionType = defaultSettings.CompressionType
 	}
 
-	t := &Topic{
-		settings:  settings,
-		name:      bl.Name(),
-		bl:        bl,
-		writer:    bl,
-		scanners:  NewTopicScannerAtomicMap(),
-		streamers: NewStreamerAtomicMap(),
+	if settings.Durability == "" {
+		settings.Durability = defaultSettings.Durability
 	}
 
-	if settings.BatchNumMessages > 1 ||
-		settings.BatchInterval.Duration() > 0 {
-		t.writer = newMessageBuffer(bl, settings)
+	if settings.CleanupPolicy == "" {
+		settings.CleanupPolicy = defaultSettings.CleanupPolicy
 	}
 
-	t.restorePersistedReaders()
-	return t
+	if settings.TombstoneRetention.Duration() == 0 {
+		settings.TombstoneRetention = defaultSettings.TombstoneRetention
+	}
+
+	if settings.MaxBytes == 0 {
+		settings.MaxBytes = defaultSettings.MaxBytes
+	}
+
+	if settings.QuotaPolicy == "" {
+		settings.QuotaPolicy = defaultSettings.QuotaPolicy
+	}
+
+	if settings.MaxSegAge.Duration() == 0 {
+		settings.MaxSegAge = defaultSettings.MaxSegAge
+	}
+
+	return settings
 }
 
 // Write implements the io.Writer interface for a Topic.
 func (t *Topic) Write(p []byte) (n int, err error) {
-	return t.writer.Write(p)
+	_, err = t.WriteMessage(p)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteMessage writes a single message to the Topic and returns the offset assigned to it.
+// If the topic batches messages the offset is known before the message is flushed to disk.
+func (t *Topic) WriteMessage(m Message) (offset int64, err error) {
+	return t.AppendN(m, 1)
 }
 
 // WriteN writes a set of N messages to the Topic
 func (t *Topic) WriteN(p []byte, n int) (written int, err error) {
-	return t.bl.WriteN(p, n)
+	_, err = t.AppendN(p, n)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// AppendN writes a set of N messages to the Topic as a single entry
+// and returns the offset assigned to the first one of them.
+func (t *Topic) AppendN(p []byte, n int) (offset int64, err error) {
+	if err = t.nl.writable(); err != nil {
+		return -1, err
+	}
+
+	if err = t.checkQuota(); err != nil {
+		return -1, err
+	}
+
+	// closing swaps the writer while holding the lock
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.nl.isClosed() {
+		return -1, ErrClosed
+	}
+
+	if offset, err = t.writer.Append(p, n); err == nil {
+		t.metrics.wrote(n, len(p))
+	}
+
+	return offset, err
+}
+
+// WriteMessages writes a batch of messages as a single entry and returns the offset
+// assigned to the first of them, the rest of the batch gets consecutive offsets.
+// Previously buffered messages are flushed first to keep the writing order.
+// The batch is stored as a message-set using the topic's compression type.
+func (t *Topic) WriteMessages(msgs []Message) (offset int64, err error) {
+	if err = t.nl.writable(); err != nil {
+		return -1, err
+	}
+
+	if err = t.checkQuota(); err != nil {
+		return -1, err
+	}
+
+	if len(msgs) == 0 {
+		return -1, ErrBadRequest
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.nl.isClosed() {
+		return -1, ErrClosed
+	}
+
+	var entry []byte
+	var size int
+	for _, m := range msgs {
+		size += len(m)
+	}
+
+	// batches are always written as message sets, like buffered ones
+	comp := t.settings.CompressionType
+	if comp == CompressionDefault {
+		comp = CompressionNone
+	}
+
+	entry = msgs[0]
+	if len(msgs) > 1 {
+		entry = MessageSet(msgs, comp)
+	}
+
+	if offset, err = t.writer.Append(entry, len(msgs)); err == nil {
+		t.metrics.wrote(len(msgs), size)
+	}
+
+	return offset, err
+}
+
+// Replicate writes an entry of n offsets copied from a leader, where `offset`
+// is the offset of the entry in the leader. Unlike the rest of writes Replicate
+// is allowed on read-only topics. ErrReplicaDiverged is returned if the offset
+// does not follow the latest offset in the topic.
+func (t *Topic) Replicate(offset int64, entry []byte, n int) error {
+	// no message can be appended between the check and the write
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.nl.isClosed() {
+		return ErrClosed
+	}
+
+	if offset != t.writer.Latest()+1 {
+		return ErrReplicaDiverged
+	}
+
+	_, err := t.appendEntry(entry, n)
+	if err == nil {
+		t.metrics.wrote(n, len(entry))
+	}
+
+	return err
+}
+
+// appendEntry writes an entry of n offsets as is after the messages buffered, t.mu must be held.
+func (t *Topic) appendEntry(entry []byte, n int) (offset int64, err error) {
+	if mb, ok := t.writer.(*messageBuffer); ok {
+		return mb.AppendEntry(entry, n)
+	}
+
+	return t.writer.Append(entry, n)
 }
 
 // Sync flushes all data to disk.
@@ -113,6 +288,14 @@ func (t *Topic) Sync() error {
 	return t.bl.Sync()
 }
 
+// Latest returns the latest offset written in the topic,
+// including messages still buffered.
+func (t *Topic) Latest() int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.writer.Latest()
+}
+
 // Name returns the Topic's name, which maps to the folder name
 func (t *Topic) Name() string {
 	return t.name
@@ -122,7 +305,12 @@ func (t *Topic) Name() string {
 // about size, segments, scanners and streamers
 type TopicInfo struct {
 	*biglog.Info
-	Scanners map[string]TScannerInfo `json:"scanners"`
+	Settings  TopicSettings           `json:"settings"`
+	Scanners  map[string]TScannerInfo `json:"scanners"`
+	Streamers int                     `json:"streamers"`
+	Groups    map[string]GroupInfo    `json:"groups"`
+	// Lagging lists the persistent scanners positioned in segments due to be discarded by age.
+	Lagging []string `json:"lagging_scanners,omitempty"`
 }
 
 // Info provides all public topic information.
@@ -138,9 +326,20 @@ func (t *Topic) Info() (i *TopicInfo, err error) {
 		scanInfo[k] = v.Info()
 	}
 
+	groups := t.groups.GetAll()
+	groupInfo := make(map[string]GroupInfo, len(groups))
+	for k, v := range groups {
+		groupInfo[k] = v.Info()
+	}
+
+	settings := t.Settings()
 	inf := &TopicInfo{
-		Info:     bi,
-		Scanners: scanInfo,
+		Info:      bi,
+		Settings:  settings,
+		Scanners:  scanInfo,
+		Streamers: t.streamers.Len(),
+		Groups:    groupInfo,
+		Lagging:   t.laggingScanners(retentionEdge(bi, settings)),
 	}
 
 	return inf, nil
@@ -156,6 +355,8 @@ type ioFlusher interface {
 // function does not flush, so calling this does not mean the data
 // has been stored on disk.
 func (t *Topic) FlushBuffered() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	if flusher, ok := t.writer.(ioFlusher); ok {
 		return flusher.Flush()
 	}
@@ -171,39 +372,31 @@ func (t *Topic) CheckSegments() error {
 		return err
 	}
 
-	err = t.checkSegmentsAge(blInfo)
-	if err != nil {
-		return err
-	}
-
-	return t.checkSegmentsSize(blInfo)
-}
-
-// Check that the oldest segment is not too old.
-func (t *Topic) checkSegmentsAge(bi *biglog.Info) error {
-	if t.settings.SegAge.Duration() <= 0 {
-		return nil
+	if t.Settings().CleanupPolicy == CleanupCompact {
+		t.checkCompaction()
+	} else {
+		err = t.checkSegmentsAge(blInfo)
 	}
 
-	if len(bi.Segments) < 2 {
-		return nil
+	if err != nil {
+		return err
 	}
 
-	if t.settings.SegAge.From(bi.Segments[0].ModTime).After(time.Now()) {
-		return nil
+	if err = t.checkSegmentsSize(blInfo); err != nil {
+		return err
 	}
 
-	log.Printf("info: removing old segment on %q", t.name)
-	return t.bl.Trim()
+	return t.checkTopicSize()
 }
 
 // Check that the hot segment is not too big.
 func (t *Topic) checkSegmentsSize(bi *biglog.Info) error {
-	if t.settings.SegSize <= 0 {
+	segSize := t.Settings().SegSize
+	if segSize <= 0 {
 		return nil
 	}
 
-	if bi.Segments[len(bi.Segments)-1].DataSize <= t.settings.SegSize {
+	if bi.Segments[len(bi.Segments)-1].DataSize <= segSize {
 		return nil
 	}
 
@@ -219,9 +412,8 @@ func (t *Topic) ReadFrom(r io.Reader) (n int64, err error) {
 	var message Message
 	for {
 		message, err = ReadMessage(r)
-		n += int64(message.Size())
 		if err == io.EOF {
-			return
+			return n, nil
 		}
 
 		if err != nil {
@@ -229,6 +421,8 @@ func (t *Topic) ReadFrom(r io.Reader) (n int64, err error) {
 			return
 		}
 
+		n += int64(message.Size())
+
 		if !message.ChecksumOK() {
 			log.Print("warn: corrupt entry in stream")
 			continue
@@ -244,39 +438,89 @@ func (t *Topic) ReadFrom(r io.Reader) (n int64, err error) {
 
 // Payload is a utility method to fetch the payload of a single offset.
 func (t *Topic) Payload(offset int64) ([]byte, error) {
+	msg, err := t.Message(offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.Payload(), nil
+}
+
+// Message is a utility method to fetch the message stored at a single offset,
+// extracting it from its message set if needed.
+func (t *Topic) Message(offset int64) (Message, error) {
+	ir, _, err := biglog.NewIndexReader(t.bl, offset)
+	if err != nil && err != biglog.ErrEmbeddedOffset {
+		return nil, err
+	}
+
+	entries, err := ir.ReadEntries(1)
+	logClose(ir)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// removed by compaction
+	if len(entries) != 1 || entries[0].Size == 0 {
+		return nil, ErrOffsetNotFound
+	}
+
 	reader, ret, err := biglog.NewReader(t.bl, offset)
 	if err != nil && err != biglog.ErrEmbeddedOffset {
 		return nil, err
 	}
 
-	entry, err := ReadMessage(reader)
+	defer logClose(reader)
+
+	// the entry may be a sequence of messages
+	entry := make(Message, entries[0].Size)
+	_, err = io.ReadFull(reader, entry)
 	if err != nil {
 		return nil, err
 	}
 
+	// ret is the first offset of the stored entry
+	// offset-ret = position of message within the entry
+	pos := int(offset - ret)
+
 	// extract list of messages out of the stored entry
 	msgs, err := Unpack(entry)
 	if err != nil {
 		return nil, err
 	}
 
-	// ret is the first offset of the returned list
-	// offset-ret = position of message within the list
-	msg := msgs[offset-ret]
+	if pos >= len(msgs) {
+		return nil, ErrOffsetNotFound
+	}
+
+	msg := msgs[pos]
 	if !msg.ChecksumOK() {
 		return nil, ErrCRC
 	}
 
-	return msg.Payload(), nil
+	t.metrics.readN(1, int64(len(msg)))
+	return msg, nil
 }
 
 // NewScanner creates a new scanner starting at offset `from`. If `persist` is true,
 // the scanner and it's state will survive server restarts
 func (t *Topic) NewScanner(from int64, persist bool) (ts TopicScanner, err error) {
-	return t.createScanner(uuid.New(), from, persist)
+	return t.createScanner(uuid.New(), from, persist, 0)
 }
 
-func (t *Topic) createScanner(ID string, from int64, persist bool) (ts TopicScanner, err error) {
+// NewAckScanner creates a new persistent scanner starting at offset `from` where
+// every scanned message must be acked, otherwise it's scanned again once the
+// visibility timeout expires.
+func (t *Topic) NewAckScanner(from int64, visibility time.Duration) (ts TopicScanner, err error) {
+	if visibility <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	return t.createScanner(uuid.New(), from, true, visibility)
+}
+
+// createScanner creates a scanner in ack mode if visibility is not zero.
+func (t *Topic) createScanner(ID string, from int64, persist bool, visibility time.Duration) (ts TopicScanner, err error) {
 	defer func() {
 		if err != nil {
 			log.Printf("warn: failed to create scanner %s:%d err: %s", t.Name(), from, err)
@@ -289,9 +533,14 @@ func (t *Topic) createScanner(ID string, from int64, persist bool) (ts TopicScan
 	}
 
 	log.Printf("info: creating scanner from offset %d", from)
-	ts, err = NewTopicScanner(t, ID, from, persist)
+	if visibility > 0 {
+		ts, err = NewAckTopicScanner(t, ID, from, visibility)
+	} else {
+		ts, err = NewTopicScanner(t, ID, from, persist)
+	}
+
 	if ts == nil || err != nil {
-		return ts, ExtErr(err)
+		return nil, ExtErr(err)
 	}
 
 	// register scanner in this topic
@@ -311,6 +560,34 @@ func (t *Topic) Scanner(ID string) (ts TopicScanner, err error) {
 	return ts, nil
 }
 
+// ScannersInfo returns the information of every scanner of the topic sorted by ID.
+func (t *Topic) ScannersInfo() []TScannerInfo {
+	scanners := t.scanners.GetAll()
+	infos := make([]TScannerInfo, 0, len(scanners))
+	for _, ts := range scanners {
+		infos = append(infos, ts.Info())
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// AckScanner acknowledges the message at offset scanned
+// by a scanner created with NewAckScanner.
+func (t *Topic) AckScanner(ID string, offset int64) error {
+	ts, err := t.Scanner(ID)
+	if err != nil {
+		return err
+	}
+
+	a, ok := ts.(*AckTopicScanner)
+	if !ok {
+		return ErrNotAckScanner
+	}
+
+	return a.Ack(offset)
+}
+
 // DeleteScanner removes the scanner from the topic
 func (t *Topic) DeleteScanner(ID string) (err error) {
 	defer func() {
@@ -336,6 +613,109 @@ func (t *Topic) DeleteScanner(ID string) (err error) {
 	return nil
 }
 
+// expireScanners deletes the non-persistent scanners idle for longer than their TTL,
+// the scans started after they are found idle fail with ErrScannerNotFound.
+func (t *Topic) expireScanners() {
+	now := time.Now()
+	for ID, ts := range t.scanners.GetAll() {
+		bts, ok := ts.(*BLTopicScanner)
+		if !ok || !bts.expire(now) {
+			continue
+		}
+
+		log.Printf("info: scanner %s of %q idle since %s, expiring", ID, t.Name(), bts.lastUsed().Format(time.RFC3339))
+		_ = t.DeleteScanner(ID)
+	}
+}
+
+// NewConsumerGroup creates a new consumer group where every
+// offset before `from` is considered already committed.
+func (t *Topic) NewConsumerGroup(name string, from int64) (g *ConsumerGroup, err error) {
+	defer func() {
+		if err != nil {
+			log.Printf("warn: failed to create group %q on %q err: %s", name, t.Name(), err)
+		}
+	}()
+
+	if g, _ = t.ConsumerGroup(name); g != nil {
+		return g, ErrGroupExists
+	}
+
+	g, err = newConsumerGroup(t, name, from)
+	if err != nil {
+		return nil, err
+	}
+
+	t.groups.Set(name, g)
+
+	log.Printf("info: created group %q on %s:%d", name, t.Name(), from)
+	return g, nil
+}
+
+// ConsumerGroup returns an existing consumer group for the topic
+// or ErrGroupNotFound if it doesn't exists.
+func (t *Topic) ConsumerGroup(name string) (*ConsumerGroup, error) {
+	g, ok := t.groups.Get(name)
+	if !ok {
+		return nil, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+// DeleteConsumerGroup removes the consumer group and its committed offset from the topic.
+func (t *Topic) DeleteConsumerGroup(name string) (err error) {
+	defer func() {
+		if err != nil {
+			log.Printf("warn: failed to delete group %q from %q err: %s", name, t.Name(), err)
+		}
+	}()
+
+	g, ok := t.groups.Get(name)
+	if !ok {
+		return ErrGroupNotFound
+	}
+
+	err = g.Close()
+	if err != nil {
+		return err
+	}
+
+	t.groups.Delete(name)
+	err = os.Remove(g.fpath)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("info: deleted group %q from %q", name, t.Name())
+	return nil
+}
+
+// NewStreamer creates a new streamer starting at offset `from`. If the offset
+// is embedded in a message-set the streamer starts at the beginning of the set.
+// The streamer must be closed once it's no longer in use.
+func (t *Topic) NewStreamer(from int64) (ts *TopicStreamer, err error) {
+	defer func() {
+		if err != nil {
+			log.Printf("warn: failed to create streamer %s:%d err: %s", t.Name(), from, err)
+		}
+	}()
+
+	if from < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	ts, err = newTopicStreamer(t, uuid.New(), from)
+	if err != nil {
+		return nil, ExtErr(err)
+	}
+
+	// register streamer in this topic
+	t.streamers.Set(ts.ID(), ts.st)
+
+	log.Printf("info: created streamer from %s:%d", t.Name(), from)
+	return ts, nil
+}
+
 // ParseOffset converts an offset string into a numeric precise offset
 // 'beginning', 'first' or 'oldest' return the lowest available offset in the topic
 // 'last' or 'latest' return the highest available offset in the topic
@@ -383,6 +763,12 @@ func (t *Topic) ParseOffset(str string) (int64, error) {
 
 // CheckIntegrity scans the topic and checks for inconsistencies in the data
 func (t *Topic) CheckIntegrity(ctx context.Context, from int64) ([]*IntegrityError, error) {
+	return t.CheckIntegrityProgress(ctx, from, 0, nil)
+}
+
+// CheckIntegrityProgress is CheckIntegrity reporting its progress
+// to fn every interval, see IntegrityChecker.OnProgress.
+func (t *Topic) CheckIntegrityProgress(ctx context.Context, from int64, interval time.Duration, fn ProgressFunc) ([]*IntegrityError, error) {
 	log.Printf("info: checking integrity of topic %q", t.Name())
 
 	ic, err := NewIntegrityChecker(t, from)
@@ -391,6 +777,10 @@ func (t *Topic) CheckIntegrity(ctx context.Context, from int64) ([]*IntegrityErr
 	}
 
 	defer logClose(ic)
+	if fn != nil {
+		ic.OnProgress(interval, fn)
+	}
+
 	iErrs := ic.Check(ctx)
 
 	log.Printf("info: integrity check finished for topic %q. Found %d errors.", t.Name(), len(iErrs))
@@ -427,24 +817,64 @@ func (t *Topic) restorePersistedReaders() {
 				continue
 			}
 
-			last := offsetFromFile(t.scannerPath(ID))
+			last, visibility := scannerFromFile(t.scannerPath(ID))
 			from := last + 1
-			if last < t.bl.Oldest() {
-				from = t.bl.Oldest()
+			if last < t.bl.Earliest() {
+				from = t.bl.Earliest()
 			}
-			_, err := t.createScanner(ID, from, true)
+			ts, err := t.createScanner(ID, from, true, visibility)
 			if err != nil {
 				log.Printf("error: unable to restore scanner %s: %s", ID, err)
 				continue
 			}
 
+			// keep the persisted position to report the offsets lost
+			if bts := blScanner(ts); bts != nil && from > last+1 {
+				log.Printf("warn: scanner %s on %s fell behind, offsets %d-%d were discarded", ID, t.name, last+1, from-1)
+				bts.mu.Lock()
+				bts.pos.Lock()
+				bts.from = last + 1
+				bts.pos.Unlock()
+				bts.mu.Unlock()
+			}
+
 			log.Printf("info: restored scanner %s on %s:%d", ID, t.name, from)
+		case groupExt:
+			name := strings.TrimSuffix(f.Name(), groupExt)
+			from := offsetFromFile(t.groupPath(name))
+			if from < t.bl.Earliest() {
+				from = t.bl.Earliest()
+			}
+
+			_, err := t.NewConsumerGroup(name, from)
+			if err != nil {
+				log.Printf("error: unable to restore group %q: %s", name, err)
+				continue
+			}
+
+			log.Printf("info: restored group %q on %s:%d", name, t.name, from)
 		default:
 			log.Printf("error: unknown file: %s", f.Name())
 		}
 	}
 }
 
+// scannerFromFile returns the last offset of a persisted scanner
+// and its visibility timeout if it was created in ack mode.
+func scannerFromFile(filePath string) (last int64, visibility time.Duration) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil || len(b) < 8 {
+		return -1, 0
+	}
+
+	last = int64(enc.Uint64(b[:8]))
+	if len(b) == 16 {
+		visibility = time.Duration(enc.Uint64(b[8:]))
+	}
+
+	return last, visibility
+}
+
 func offsetFromFile(filePath string) int64 {
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Settings returns the current settings of the topic.
+func (t *Topic) Settings() TopicSettings {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.settings
+}
+
+// UpdateSettings validates, persists and applies new settings to the topic, replacing
+// the current ones. As on creation empty settings take the default of the server.
+// Messages buffered when batching or compression change are flushed before the
+// new settings apply, retention and size limits apply on the next segment check.
+func (t *Topic) UpdateSettings(settings TopicSettings) error {
+	if err := t.nl.writable(); err != nil {
+		return err
+	}
+
+	if err := validateSettings(settings); err != nil {
+		return err
+	}
+
+	settings = withDefaults(settings, t.nl.topicSettings)
+	if err := t.applySettings(settings); err != nil {
+		return err
+	}
+
+	// lift or apply a write rejection right away
+	if err := t.checkTopicSize(); err != nil {
+		log.Printf("error: failed to check size of %q: %s", t.name, err)
+	}
+
+	return nil
+}
+
+func (t *Topic) applySettings(settings TopicSettings) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// the writer of closed topics must not be replaced
+	if t.nl.isClosed() {
+		return ErrClosed
+	}
+
+	old := t.settings
+	swap := settings.BatchNumMessages != old.BatchNumMessages ||
+		settings.BatchInterval.Duration() != old.BatchInterval.Duration() ||
+		settings.CompressionType != old.CompressionType
+
+	// nothing is appended while the lock is held, so
+	// no message is left behind in the old buffer
+	if flusher, ok := t.writer.(ioFlusher); ok && swap {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+
+	err := writeSettings(filepath.Join(t.DirPath(), settingsFile), settings)
+	if err != nil {
+		return err
+	}
+
+	if swap {
+		if mb, ok := t.writer.(*messageBuffer); ok {
+			logClose(mb)
+		}
+
+		t.writer = newWriter(t.bl, settings, t.metrics)
+	}
+
+	t.settings = settings
+	return nil
+}
+
+// newWriter returns the writer of a topic, buffering
+// messages in memory if the settings enable batching.
+func newWriter(bl appender, settings TopicSettings, tm *topicMetrics) appender {
+	if settings.BatchNumMessages > 1 ||
+		settings.BatchInterval.Duration() > 0 {
+		return newMessageBuffer(bl, settings, tm)
+	}
+
+	return bl
+}
+
+// validateSettings checks the settings given on creation or update.
+func validateSettings(settings TopicSettings) (err error) {
+	if settings.SegSize < 0 || settings.BatchNumMessages < 0 || settings.MaxBytes < 0 {
+		return ErrBadRequest
+	}
+
+	if settings.CompressionType > CompressionSnappy {
+		return ErrInvalidCompression
+	}
+
+	if _, err = ParseDurability(string(settings.Durability)); err != nil {
+		return err
+	}
+
+	if _, err = ParseCleanupPolicy(string(settings.CleanupPolicy)); err != nil {
+		return err
+	}
+
+	_, err = ParseQuotaPolicy(string(settings.QuotaPolicy))
+	return err
+}
+
+// writeSettings persists the settings replacing the file at path atomically.
+func writeSettings(path string, settings TopicSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+	}
+
+	return err
+}
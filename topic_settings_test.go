@@ -0,0 +1,82 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ninibe/bigduration"
+)
+
+func TestUpdateSettings(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	interval, err := bigduration.ParseBigDuration("1day")
+	panicOn(err)
+
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		BatchNumMessages: 1000,
+		BatchInterval:    interval,
+	})
+	panicOn(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = topic.WriteMessage(MessageFromPayload(randData(32)))
+		panicOn(err)
+	}
+
+	if topic.bl.Latest() != -1 {
+		t.Fatal("Messages not buffered")
+	}
+
+	if err = topic.UpdateSettings(TopicSettings{Durability: "eventually"}); err != ErrInvalidDurability {
+		t.Errorf("Expected ErrInvalidDurability, got %v", err)
+	}
+
+	if err = topic.UpdateSettings(TopicSettings{CompressionType: 9}); err != ErrInvalidCompression {
+		t.Errorf("Expected ErrInvalidCompression, got %v", err)
+	}
+
+	if topic.Settings().BatchNumMessages != 1000 {
+		t.Error("Invalid settings applied")
+	}
+
+	// writers racing the update lose nothing
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 50; k++ {
+				_, err2 := topic.WriteMessage(MessageFromPayload(randData(32)))
+				panicOn(err2)
+			}
+		}()
+	}
+
+	panicOn(topic.UpdateSettings(TopicSettings{BatchNumMessages: 1, SegSize: 4096}))
+	wg.Wait()
+
+	if latest := topic.bl.Latest(); latest != 202 {
+		t.Errorf("Latest offset %d on the log after the update. Expected 202", latest)
+	}
+
+	if _, ok := topic.writer.(*messageBuffer); ok {
+		t.Error("Messages still buffered after disabling batching")
+	}
+
+	nl2, err := NewNetLog(nl.dataDir)
+	panicOn(err)
+	topic2, err := nl2.Topic(topicName)
+	panicOn(err)
+	if s := topic2.Settings(); s.SegSize != 4096 || s.BatchNumMessages != 1 {
+		t.Errorf("Settings not persisted %+v", s)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
@@ -0,0 +1,199 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/ninibe/netlog/biglog"
+)
+
+const (
+	deltaOffsetPos  = 0  // Offset int64
+	deltaODeltaPos  = 8  // ODelta int64
+	deltaSizePos    = 16 // Size   int64
+	deltaHeaderSize = 24
+)
+
+// DeltaHeader precedes every chunk of raw data sent by a TopicStreamer.
+// The data that follows is a sequence of Size bytes of stored messages,
+// some of which may be message-sets holding several offsets.
+type DeltaHeader struct {
+	// Offset of the first message in the chunk.
+	Offset int64 `json:"offset"`
+	// ODelta is the number of offsets held by the chunk.
+	ODelta int64 `json:"odelta"`
+	// Size is the number of bytes following the header.
+	Size int64 `json:"size"`
+}
+
+// Bytes returns the binary representation of the header.
+func (h DeltaHeader) Bytes() []byte {
+	buf := make([]byte, deltaHeaderSize)
+	enc.PutUint64(buf[deltaOffsetPos:deltaODeltaPos], uint64(h.Offset))
+	enc.PutUint64(buf[deltaODeltaPos:deltaSizePos], uint64(h.ODelta))
+	enc.PutUint64(buf[deltaSizePos:deltaHeaderSize], uint64(h.Size))
+	return buf
+}
+
+// ReadDeltaHeader reads a DeltaHeader from r.
+func ReadDeltaHeader(r io.Reader) (h DeltaHeader, err error) {
+	buf := make([]byte, deltaHeaderSize)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return h, err
+	}
+
+	h.Offset = int64(enc.Uint64(buf[deltaOffsetPos:deltaODeltaPos]))
+	h.ODelta = int64(enc.Uint64(buf[deltaODeltaPos:deltaSizePos]))
+	h.Size = int64(enc.Uint64(buf[deltaSizePos:deltaHeaderSize]))
+	return h, nil
+}
+
+// TopicStreamer reads a topic in raw chunks of stored data blocking
+// until new data is written. TopicStreamers are thread-safe.
+type TopicStreamer struct {
+	mu    sync.Mutex
+	_ID   string
+	topic *Topic
+
+	st *biglog.Streamer
+	wc *biglog.Watcher
+}
+
+// newTopicStreamer returns a streamer starting at offset `from`, or at the
+// first offset of the message-set containing it if `from` is embedded.
+func newTopicStreamer(t *Topic, ID string, from int64) (*TopicStreamer, error) {
+	st, err := biglog.NewStreamer(t.bl, from)
+	if err != nil && err != biglog.ErrEmbeddedOffset {
+		return nil, err
+	}
+
+	ts := &TopicStreamer{
+		_ID:   ID,
+		topic: t,
+		st:    st,
+		wc:    biglog.NewWatcher(t.bl),
+	}
+
+	return ts, nil
+}
+
+// ID returns the ID of the streamer.
+func (ts *TopicStreamer) ID() string {
+	return ts._ID
+}
+
+// Next writes into w the next chunk of data holding at most maxOffsets
+// offsets and maxBytes bytes preceded by its DeltaHeader. Next blocks until
+// there is data available or the context is done. ErrDeltaLimits is returned
+// if the next stored entry does not fit the given limits.
+func (ts *TopicStreamer) Next(ctx context.Context, w io.Writer, maxOffsets, maxBytes int64) (n int64, err error) {
+	return ts.next(ctx, w, maxOffsets, maxBytes, ts.st.Get)
+}
+
+// NextEntry works like Next but the chunk written holds a single stored entry,
+// so the data can be written elsewhere keeping the boundaries of the entries.
+func (ts *TopicStreamer) NextEntry(ctx context.Context, w io.Writer, maxOffsets, maxBytes int64) (n int64, err error) {
+	return ts.next(ctx, w, maxOffsets, maxBytes, ts.st.GetEntry)
+}
+
+func (ts *TopicStreamer) next(ctx context.Context, w io.Writer, maxOffsets, maxBytes int64,
+	get func(maxOffsets, maxBytes int64) (*biglog.StreamDelta, error)) (n int64, err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for {
+		delta, err := get(maxOffsets, maxBytes)
+		if err == nil {
+			return ts.write(w, delta)
+		}
+
+		if err != io.EOF {
+			return 0, ExtErr(err)
+		}
+
+		// block until done or new data
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ts.wc.Watch():
+			continue
+		}
+	}
+}
+
+func (ts *TopicStreamer) write(w io.Writer, delta *biglog.StreamDelta) (n int64, err error) {
+	defer func() {
+		if pErr := ts.st.Put(delta); pErr != nil && err == nil {
+			err = pErr
+		}
+	}()
+
+	h := DeltaHeader{
+		Offset: delta.Offset(),
+		ODelta: delta.OffsetDelta(),
+		Size:   delta.Size(),
+	}
+
+	hn, err := w.Write(h.Bytes())
+	n += int64(hn)
+	if err != nil {
+		return n, err
+	}
+
+	dn, err := io.Copy(w, delta)
+	n += dn
+	if err == nil {
+		ts.topic.metrics.readN(int(h.ODelta), h.Size)
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer and releases the TopicStreamer resources.
+func (ts *TopicStreamer) Close() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.topic.streamers.Delete(ts._ID)
+	if err := ts.st.Close(); err != nil {
+		log.Printf("error: failed to close streamer %s: %s", ts._ID, err)
+		return err
+	}
+
+	return ts.wc.Close()
+}
+
+// UnpackDelta returns the individual messages held by the data of a delta,
+// unpacking any message-sets found in it. Being Offset the offset of the
+// delta, the message at position i in the result has offset Offset+i.
+func UnpackDelta(data []byte) (msgs []Message, err error) {
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		m, err := ReadMessage(r)
+		if err != nil {
+			return msgs, err
+		}
+
+		if m.Compression() == CompressionDefault {
+			msgs = append(msgs, m)
+			continue
+		}
+
+		set, err := Unpack(m)
+		if err != nil {
+			return msgs, err
+		}
+
+		msgs = append(msgs, set...)
+	}
+
+	return msgs, nil
+}
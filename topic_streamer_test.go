@@ -0,0 +1,154 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTopicStreamer(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	messages := randMessageSet()
+	for _, m := range messages[:10] {
+		_, err = topic.Write(m)
+		panicOn(err)
+	}
+
+	ts, err := topic.NewStreamer(0)
+	panicOn(err)
+
+	if info, _ := topic.Info(); info.Streamers != 1 {
+		t.Errorf("Streamer not registered in topic info")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	buf := &bytes.Buffer{}
+	_, err = ts.Next(ctx, buf, 5, 1024*1024)
+	panicOn(err)
+
+	h, err := ReadDeltaHeader(buf)
+	panicOn(err)
+
+	if h.Offset != 0 || h.ODelta != 5 || h.Size != int64(buf.Len()) {
+		t.Errorf("Invalid delta header %+v with %d bytes of data", h, buf.Len())
+	}
+
+	for _, m := range messages[:5] {
+		m2, err2 := ReadMessage(buf)
+		panicOn(err2)
+		if !bytes.Equal(m, m2) {
+			t.Errorf("Bad stream. Message not equal to original.\n Got: % x\n Exp: % x\n", m2, m)
+		}
+	}
+
+	// block until new data is written
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, err2 := topic.WriteN(bytes.Join([][]byte{messages[10], messages[11]}, nil), 2)
+		panicOn(err2)
+	}()
+
+	buf.Reset()
+	_, err = ts.Next(ctx, buf, 100, 1024*1024)
+	panicOn(err)
+
+	h, err = ReadDeltaHeader(buf)
+	panicOn(err)
+	if h.Offset != 5 || h.ODelta != 5 {
+		t.Errorf("Invalid delta header %+v", h)
+	}
+
+	buf.Reset()
+	_, err = ts.Next(ctx, buf, 100, 1024*1024)
+	panicOn(err)
+
+	h, err = ReadDeltaHeader(buf)
+	panicOn(err)
+	if h.Offset != 10 || h.ODelta != 2 {
+		t.Errorf("Invalid delta header %+v", h)
+	}
+
+	// no more data, must block until cancelled
+	cctx, ccancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer ccancel()
+	_, err = ts.Next(cctx, buf, 100, 1024*1024)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	err = ts.Close()
+	panicOn(err)
+
+	if info, _ := topic.Info(); info.Streamers != 0 {
+		t.Errorf("Streamer not removed from topic info")
+	}
+}
+
+func TestTopicStreamerLimits(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	_, err = topic.Write(MessageFromPayload(randData(100)))
+	panicOn(err)
+
+	ts, err := topic.NewStreamer(0)
+	panicOn(err)
+	defer logClose(ts)
+
+	_, err = ts.Next(context.Background(), &bytes.Buffer{}, 100, 10)
+	if err != ErrDeltaLimits {
+		t.Errorf("Expected ErrDeltaLimits, got %v", err)
+	}
+}
+
+func TestUnpackDelta(t *testing.T) {
+	t.Parallel()
+
+	messages := randMessageSet()
+	data := bytes.Join([][]byte{
+		messages[0],
+		MessageSet(messages[1:5], CompressionGzip),
+		messages[5],
+		MessageSet(messages[6:8], CompressionNone),
+	}, nil)
+
+	msgs, err := UnpackDelta(data)
+	panicOn(err)
+
+	if len(msgs) != 8 {
+		t.Fatalf("Expected 8 messages got %d", len(msgs))
+	}
+
+	for k, m := range msgs {
+		if !bytes.Equal(m, messages[k]) {
+			t.Errorf("Message %d not equal to original.\n Got: % x\n Exp: % x\n", k, m, messages[k])
+		}
+	}
+}
@@ -5,8 +5,12 @@
 package netlog
 
 import (
+	"bytes"
 	"testing"
 	"time"
+
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog/biglog"
 )
 
 func TestTopicCreateGetDelete(t *testing.T) {
@@ -98,3 +102,136 @@ func TestParseOffset(t *testing.T) {
 		}
 	}
 }
+
+func TestTopicWriteMessages(t *testing.T) {
+	t.Parallel()
+
+	for _, comp := range []CompressionType{CompressionDefault, CompressionNone, CompressionSnappy} {
+		nl := tempNetLog()
+		topicName := randStr(6)
+		topic, err := nl.CreateTopic(topicName, TopicSettings{
+			BatchNumMessages: 10,
+			CompressionType:  comp,
+		})
+		panicOn(err)
+
+		messages := randMessageSet()
+
+		// buffered messages must be written first
+		_, err = topic.Write(messages[0])
+		panicOn(err)
+
+		offset, err := topic.WriteMessages(messages[1:])
+		panicOn(err)
+		if offset != 1 {
+			t.Errorf("Expected batch at offset 1 got %d", offset)
+		}
+
+		if latest := topic.Latest(); latest != int64(len(messages)-1) {
+			t.Errorf("Expected latest offset %d got %d", len(messages)-1, latest)
+		}
+
+		for k, m := range messages {
+			p, err := topic.Payload(int64(k))
+			panicOn(err)
+			if !bytes.Equal(p, m.Payload()) {
+				t.Errorf("Bad payload at offset %d with compression %d", k, comp)
+			}
+		}
+
+		// the batch is a single entry holding all its offsets
+		sc, err := biglog.NewScanner(topic.bl, 1)
+		panicOn(err)
+		if !sc.Scan() {
+			t.Fatalf("Failed to scan batch: %v", sc.Err())
+		}
+
+		n, err := EntryOffsets(sc.Bytes())
+		panicOn(err)
+		if n != len(messages)-1 || sc.ODelta() != n {
+			t.Errorf("Batch stored with %d offsets counting %d with compression %d", sc.ODelta(), n, comp)
+		}
+		panicOn(sc.Close())
+
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}
+}
+
+func TestTopicWriteMessageOffset(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	bd, err := bigduration.ParseBigDuration("1h")
+	panicOn(err)
+
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		BatchNumMessages: 7,
+		BatchInterval:    bd,
+		CompressionType:  CompressionGzip,
+	})
+	panicOn(err)
+
+	messages := randMessageSet()
+	for k, m := range messages {
+		offset, err := topic.WriteMessage(m)
+		panicOn(err)
+		if offset != int64(k) {
+			t.Errorf("Message %d written at offset %d", k, offset)
+		}
+	}
+
+	// a batch after buffered messages
+	offset, err := topic.WriteMessages(messages[:3])
+	panicOn(err)
+	if offset != int64(len(messages)) {
+		t.Errorf("Batch written at offset %d expected %d", offset, len(messages))
+	}
+
+	for k, m := range messages {
+		p, err := topic.Payload(int64(k))
+		panicOn(err)
+		if !bytes.Equal(p, m.Payload()) {
+			t.Errorf("Bad payload at offset %d", k)
+		}
+	}
+
+	err = nl.DeleteTopic(topicName, true)
+	panicOn(err)
+}
+
+func TestTopicReplicateAfterBuffered(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	bd, err := bigduration.ParseBigDuration("1h")
+	panicOn(err)
+
+	topic, err := nl.CreateTopic(topicName, TopicSettings{
+		BatchNumMessages: 10,
+		BatchInterval:    bd,
+	})
+	panicOn(err)
+
+	messages := randMessageSet()[:3]
+	for _, m := range messages[:2] {
+		_, err = topic.WriteMessage(m)
+		panicOn(err)
+	}
+
+	// the entry follows the messages buffered
+	panicOn(topic.Replicate(2, messages[2], 1))
+	panicOn(topic.FlushBuffered())
+
+	for k, m := range messages {
+		p, err := topic.Payload(int64(k))
+		panicOn(err)
+		if !bytes.Equal(p, m.Payload()) {
+			t.Errorf("Bad payload at offset %d", k)
+		}
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
@@ -11,6 +11,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ninibe/netlog/biglog"
 )
@@ -24,6 +26,7 @@ var scannerPattern = "%36s.scanner"
 type TopicScanner interface {
 	ID() string
 	Scan(ctx context.Context) (m Message, offset int64, err error)
+	ScanN(ctx context.Context, maxMessages int, maxBytes int64) (msgs []Message, offsets []int64, err error)
 	Info() TScannerInfo
 	Close() error
 }
@@ -37,20 +40,45 @@ func NewTopicScanner(t *Topic, ID string, from int64, persist bool) (TopicScanne
 	}
 
 	if !persist {
+		if t.nl != nil {
+			bts.ttl = t.nl.scannerTTL.Duration()
+		}
+
 		return bts, nil
 	}
 
-	return newPersistentTopicScanner(t, bts)
+	pts, err := newPersistentTopicScanner(t, bts, from)
+	if err != nil {
+		logClose(bts)
+		return nil, err
+	}
+
+	return pts, nil
 }
 
 // BLTopicScanner implements TopicScanner reading from BigLog.
 type BLTopicScanner struct {
-	mu       sync.RWMutex
-	_ID      string
-	topic    *Topic
+	lastUse  int64 // unix nanoseconds of the last scan started or finished, atomic
+	scanning int32 // number of scans in progress, atomic
+	ttl      time.Duration
+
+	// state serializes starting scans with expiring the scanner, it's never held while scanning
+	state   sync.Mutex
+	expired bool
+
+	_ID    string
+	topic  *Topic
+	loaded time.Time // when the scanner was created or restored
+
+	mu       sync.Mutex // held while scanning, waiting for new data included
+	messages []Message
+
+	// pos guards the position so it can be read while a scan waits,
+	// writers must also hold mu.
+	pos      sync.RWMutex
 	from     int64
 	last     int64
-	messages []Message
+	lastScan time.Time // when the scanner last returned messages
 
 	sc *biglog.Scanner
 	wc *biglog.Watcher
@@ -64,12 +92,13 @@ func newBLTopicScanner(t *Topic, ID string, from int64) (bts *BLTopicScanner, er
 	}
 
 	bts = &BLTopicScanner{
-		_ID:   ID,
-		topic: t,
-		from:  from,
-		last:  -1,
-		sc:    sc,
-		wc:    biglog.NewWatcher(t.bl),
+		_ID:    ID,
+		topic:  t,
+		from:   from,
+		last:   -1,
+		loaded: time.Now(),
+		sc:     sc,
+		wc:     biglog.NewWatcher(t.bl),
 	}
 
 	// auto-scan forward if embedded offset
@@ -80,69 +109,181 @@ func newBLTopicScanner(t *Topic, ID string, from int64) (bts *BLTopicScanner, er
 	return bts, err
 }
 
-// scans in a loop until the next offset is the target offset
-func (ts *BLTopicScanner) scanForward(target int64) (err error) {
-	ctx := context.Background()
-	var offset int64
-	for {
-		_, offset, err = ts.Scan(ctx)
-		// escape when next is the target offset
-		if offset+1 == target {
-			break
-		}
+// scanForward skips the messages of the current entry until the next offset
+// is the target offset, or the first one after it if it was compacted.
+func (ts *BLTopicScanner) scanForward(target int64) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
 
-		if err != nil {
-			return err
+	for ts.last+1 < target {
+		if len(ts.messages) == 0 {
+			if err := ts.fill(doneCtx); err != nil {
+				return err
+			}
+
+			continue
 		}
+
+		ts.setLast(ts.last + 1)
+		ts.messages = ts.messages[1:]
 	}
 
-	return err
+	return nil
 }
 
 // Scan advances the Scanner to the next message, returning the message and the offset.
 // Scan will block when it reaches EOF until there is more data available,
 // the user must provide a context to cancel the request when it needs to stop waiting.
 func (ts *BLTopicScanner) Scan(ctx context.Context) (m Message, offset int64, err error) {
-	ts.mu.Lock()
-	defer ts.mu.Unlock()
-
-	for {
-		// if there is a buffered message
-		//  from a set return one of those
-		if len(ts.messages) > 0 {
-			m = ts.messages[0]
-			offset = ts.last
+	return scanOne(ctx, ts)
+}
 
-			ts.last++
-			ts.messages = ts.messages[1:]
+// ScanN advances the Scanner up to maxMessages messages or maxBytes bytes, returning the
+// messages and their offsets. ScanN blocks like Scan only until the first message is
+// available, which is returned even if it's bigger than maxBytes. Zero maxBytes means no limit.
+func (ts *BLTopicScanner) ScanN(ctx context.Context, maxMessages int, maxBytes int64) (msgs []Message, offsets []int64, err error) {
+	if !ts.touch(1) {
+		return nil, nil, ErrScannerNotFound
+	}
 
-			return m, offset, nil
-		}
+	defer ts.touch(-1)
 
-		// scan a new entry
-		ok := ts.scan(ctx)
-		if ok {
-			ts.last = ts.sc.Offset()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
 
-			// if it's got only one message return it
-			if ts.sc.ODelta() == 1 {
-				return Message(ts.sc.Bytes()), ts.last, nil
+	var size int64
+	for len(msgs) < maxMessages {
+		if len(ts.messages) == 0 {
+			// only wait for the first message
+			sctx := ctx
+			if len(msgs) > 0 {
+				sctx = doneCtx
 			}
 
-			// unpack message-set into buffer
-			ts.messages, err = Unpack(ts.sc.Bytes())
-		}
+			err = ts.fill(sctx)
+			if err != nil {
+				break
+			}
 
-		if ts.sc.Err() != nil {
-			return nil, -1, ts.sc.Err()
+			// compacted offsets
+			if len(ts.messages) == 0 {
+				continue
+			}
 		}
 
-		if !ok {
+		m := ts.messages[0]
+		if len(msgs) > 0 && maxBytes > 0 && size+int64(len(m)) > maxBytes {
 			break
 		}
+
+		size += int64(len(m))
+		ts.setLast(ts.last + 1)
+		ts.messages = ts.messages[1:]
+
+		msgs = append(msgs, m)
+		offsets = append(offsets, ts.last)
+	}
+
+	if len(msgs) > 0 {
+		ts.pos.Lock()
+		ts.lastScan = time.Now()
+		ts.pos.Unlock()
+		ts.topic.metrics.readN(len(msgs), size)
+		return msgs, offsets, nil
+	}
+
+	return nil, nil, err
+}
+
+// setLast sets the offset of the last message scanned, ts.mu must be held.
+func (ts *BLTopicScanner) setLast(last int64) {
+	ts.pos.Lock()
+	ts.last = last
+	ts.pos.Unlock()
+}
+
+// touch renews the TTL of the scanner and adds delta to the scans in progress,
+// it returns false without doing so if the scanner already expired.
+func (ts *BLTopicScanner) touch(delta int32) bool {
+	ts.state.Lock()
+	defer ts.state.Unlock()
+	if ts.expired {
+		return false
+	}
+
+	atomic.StoreInt64(&ts.lastUse, time.Now().UnixNano())
+	atomic.AddInt32(&ts.scanning, delta)
+	return true
+}
+
+// expire marks the scanner as expired if it has a TTL and was not used for
+// longer than it, returning true if it did. Expired scanners can't scan anymore.
+func (ts *BLTopicScanner) expire(now time.Time) bool {
+	ts.state.Lock()
+	defer ts.state.Unlock()
+	if ts.ttl <= 0 || atomic.LoadInt32(&ts.scanning) > 0 || now.Sub(ts.lastUsed()) <= ts.ttl {
+		return false
+	}
+
+	ts.expired = true
+	return true
+}
+
+// lastUsed returns when the scanner was last used, or loaded if it never was.
+func (ts *BLTopicScanner) lastUsed() time.Time {
+	if n := atomic.LoadInt64(&ts.lastUse); n > 0 {
+		return time.Unix(0, n)
 	}
 
-	return nil, -1, ErrEndOfTopic
+	return ts.loaded
+}
+
+// fill scans the next entry into the messages buffer, ts.mu must be held.
+func (ts *BLTopicScanner) fill(ctx context.Context) (err error) {
+	ok := ts.scan(ctx)
+	if ts.sc.Err() != nil {
+		return ts.sc.Err()
+	}
+
+	if !ok {
+		return ErrEndOfTopic
+	}
+
+	// last is the offset before the entry
+	ts.setLast(ts.sc.Offset() - 1)
+
+	// offsets removed by compaction
+	if len(ts.sc.Bytes()) == 0 {
+		ts.setLast(ts.last + int64(ts.sc.ODelta()))
+		ts.messages = nil
+		return nil
+	}
+
+	// the scanner reuses its buffer
+	if ts.sc.ODelta() == 1 {
+		ts.messages = []Message{append(Message(nil), ts.sc.Bytes()...)}
+		return nil
+	}
+
+	ts.messages, err = Unpack(ts.sc.Bytes())
+	return err
+}
+
+// doneCtx is a context already done used to scan without waiting.
+var doneCtx = func() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}()
+
+// scanOne implements Scan on top of ScanN.
+func scanOne(ctx context.Context, ts TopicScanner) (m Message, offset int64, err error) {
+	msgs, offsets, err := ts.ScanN(ctx, 1, 0)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return msgs[0], offsets[0], nil
 }
 
 func (ts *BLTopicScanner) scan(ctx context.Context) bool {
@@ -172,9 +313,6 @@ func (ts *BLTopicScanner) scan(ctx context.Context) bool {
 
 // ID returns the ID of the scanner
 func (ts *BLTopicScanner) ID() string {
-	ts.mu.RLock()
-	defer ts.mu.RUnlock()
-
 	return ts._ID
 }
 
@@ -193,30 +331,89 @@ type TScannerInfo struct {
 	Next    int64  `json:"next"`
 	From    int64  `json:"from"`
 	Persist bool   `json:"persistent"`
+	Ack     bool   `json:"ack,omitempty"`
+	Pending int    `json:"pending,omitempty"`
+	// Lag is the number of messages stored after the position of the scanner and LagBytes their size.
+	Lag      int64 `json:"lag"`
+	LagBytes int64 `json:"lag_bytes"`
+	// LastScan is when the scanner last returned messages, zero if it never did.
+	LastScan time.Time `json:"last_scan"`
+	// IdleSeconds is the time since the last scan, or since the scanner was loaded if it never scanned.
+	IdleSeconds float64 `json:"idle_seconds"`
+	// Behind is set when the position of the scanner fell behind the earliest offset of
+	// the topic, the messages in between were discarded before being scanned.
+	Behind bool `json:"behind"`
+	// TTL is the idle time after which a non-persistent scanner is removed, unset if it never expires,
+	// and ExpiresAt when that happens unless it scans before.
+	TTL       float64    `json:"ttl_seconds,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // Info returns a TScannerInfo struct with the scanner's
-// next offset and the initial offset.
+// next offset, the initial offset and its lag.
+// It doesn't wait for the scans in progress.
 func (ts *BLTopicScanner) Info() TScannerInfo {
-	ts.mu.RLock()
-	defer ts.mu.RUnlock()
+	ts.pos.RLock()
+	defer ts.pos.RUnlock()
+
+	info := TScannerInfo{
+		ID:       ts._ID,
+		Next:     ts.next(),
+		From:     ts.from,
+		LastScan: ts.lastScan,
+	}
+
+	if latest := ts.topic.bl.Latest(); info.Next <= latest {
+		info.Lag = latest - info.Next + 1
+		if size, err := ts.topic.bl.DataSizeFrom(info.Next); err == nil {
+			info.LagBytes = size
+		}
+	}
 
-	return TScannerInfo{
-		ID:   ts._ID,
-		Next: ts.next(),
-		From: ts.from,
+	idleSince := ts.lastScan
+	if idleSince.IsZero() {
+		idleSince = ts.loaded
 	}
+
+	info.IdleSeconds = time.Since(idleSince).Seconds()
+	info.Behind = ts.position() < ts.topic.bl.Earliest()
+	if ts.ttl > 0 {
+		expires := ts.lastUsed().Add(ts.ttl)
+		info.TTL = ts.ttl.Seconds()
+		info.ExpiresAt = &expires
+	}
+
+	return info
 }
 
-// next returns next index for the scanner
-func (ts *BLTopicScanner) next() (next int64) {
+// position returns the offset the scanner is at, before clamping it to the earliest one.
+// ts.mu or ts.pos must be held.
+func (ts *BLTopicScanner) position() int64 {
 	if ts.last < 0 {
-		next = ts.from
-	} else {
-		next = ts.last + 1
+		return ts.from
+	}
+
+	return ts.last + 1
+}
+
+// blScanner returns the BLTopicScanner wrapped by a scanner.
+func blScanner(ts TopicScanner) *BLTopicScanner {
+	switch s := ts.(type) {
+	case *BLTopicScanner:
+		return s
+	case *PersistentTopicScanner:
+		return blScanner(s.ts)
+	case *AckTopicScanner:
+		return blScanner(s.ts)
 	}
 
-	oldest := ts.topic.bl.Oldest()
+	return nil
+}
+
+// next returns next index for the scanner
+func (ts *BLTopicScanner) next() (next int64) {
+	next = ts.position()
+	oldest := ts.topic.bl.Earliest()
 	if oldest > next {
 		next = oldest
 	}
@@ -225,7 +422,7 @@ func (ts *BLTopicScanner) next() (next int64) {
 }
 
 // newPersistentTopicScanner returns a new topic scanner wrapper which will persist the scanners state
-func newPersistentTopicScanner(t *Topic, ts TopicScanner) (*PersistentTopicScanner, error) {
+func newPersistentTopicScanner(t *Topic, ts TopicScanner, from int64) (*PersistentTopicScanner, error) {
 
 	err := os.MkdirAll(t.readersDir(), 0755)
 	if err != nil {
@@ -242,11 +439,28 @@ func newPersistentTopicScanner(t *Topic, ts TopicScanner) (*PersistentTopicScann
 		return nil, ErrInvalidDir
 	}
 
+	// restored scanners start at the offset persisted, new ones at `from`
+	last, _ := scannerFromFile(fpath)
+	persisted := last >= 0
+	if !persisted {
+		last = from - 1
+	}
+
 	pts := &PersistentTopicScanner{
+		last:  last,
 		f:     f,
 		fpath: fpath,
 		ts:    ts,
 		oc:    make(chan int64, 100),
+		done:  make(chan struct{}),
+	}
+
+	// so it also resumes at `from` if restarted before scanning
+	if !persisted && last >= 0 {
+		if err = pts.write(last); err != nil {
+			logClose(f)
+			return nil, err
+		}
 	}
 
 	go pts.persist()
@@ -256,10 +470,15 @@ func newPersistentTopicScanner(t *Topic, ts TopicScanner) (*PersistentTopicScann
 // PersistentTopicScanner synchronizes the underlying
 // scanner state to a given writer
 type PersistentTopicScanner struct {
+	last  int64 // last offset scanned or restored, atomic
 	f     *os.File
 	fpath string
 	ts    TopicScanner
 	oc    chan int64
+	done  chan struct{}
+
+	mu     sync.Mutex // guards closed and sending into oc
+	closed bool
 }
 
 // ID the ID of the scanner
@@ -269,17 +488,55 @@ func (p *PersistentTopicScanner) ID() string {
 
 // Scan offloads the actual scan to the underlying scanner while updates the last read offset
 func (p *PersistentTopicScanner) Scan(ctx context.Context) (m Message, offset int64, err error) {
-	m, offset, err = p.ts.Scan(ctx)
-	if offset < 0 {
-		return m, offset, err
+	return scanOne(ctx, p)
+}
+
+// ScanN offloads the actual scan to the underlying scanner while updates the last read offset
+func (p *PersistentTopicScanner) ScanN(ctx context.Context, maxMessages int, maxBytes int64) (msgs []Message, offsets []int64, err error) {
+	if p.isClosed() {
+		return nil, nil, ErrClosed
 	}
 
+	msgs, offsets, err = p.ts.ScanN(ctx, maxMessages, maxBytes)
+	if len(offsets) == 0 {
+		return msgs, offsets, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// closed while scanning, the position can't be persisted anymore
+	if p.closed {
+		return nil, nil, ErrClosed
+	}
+
+	last := offsets[len(offsets)-1]
+	atomic.StoreInt64(&p.last, last)
 	select {
-	case p.oc <- offset:
+	case p.oc <- last:
 	default:
 	}
 
-	return m, offset, err
+	return msgs, offsets, err
+}
+
+func (p *PersistentTopicScanner) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// stop stops persisting offsets, returning false if it was already stopped.
+func (p *PersistentTopicScanner) stop() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+
+	p.closed = true
+	close(p.oc)
+	return true
 }
 
 // Info returns a TScannerInfo struct with the scanner's
@@ -293,23 +550,58 @@ func (p *PersistentTopicScanner) Info() TScannerInfo {
 // Close deletes the offset tracking file, closes the
 // offset channel and closes the underlying scanner
 func (p *PersistentTopicScanner) Close() error {
+	if !p.stop() {
+		return ErrClosed
+	}
+
+	<-p.done
+	logClose(p.f)
 	err := os.Remove(p.fpath)
 	if err != nil {
 		log.Printf("error: can't remove %s: %s", p.fpath, err)
 		return err
 	}
 
-	close(p.oc)
+	return p.ts.Close()
+}
+
+// release closes the underlying scanner keeping the offset tracking file, where
+// the last offset scanned is written, so the scanner is restored on the next start.
+func (p *PersistentTopicScanner) release() error {
+	if !p.stop() {
+		return ErrClosed
+	}
+
+	<-p.done
+
+	// offsets are dropped while the channel is full
+	if last := atomic.LoadInt64(&p.last); last >= 0 {
+		if err := p.write(last); err != nil {
+			return err
+		}
+	}
+
+	if err := p.f.Close(); err != nil {
+		return err
+	}
+
 	return p.ts.Close()
 }
 
 func (p *PersistentTopicScanner) persist() {
-	buf := make([]byte, 8)
+	defer close(p.done)
 	for o := range p.oc {
-		enc.PutUint64(buf, uint64(o))
-		_, err := p.f.WriteAt(buf, 0)
-		if err != nil {
-			log.Printf("error: failed to persist topic scanner %s: %s", p.ts.Info().ID, err)
-		}
+		_ = p.write(o)
+	}
+}
+
+func (p *PersistentTopicScanner) write(offset int64) error {
+	buf := make([]byte, 8)
+	enc.PutUint64(buf, uint64(offset))
+	_, err := p.f.WriteAt(buf, 0)
+	if err != nil {
+		log.Printf("error: failed to persist topic scanner %s: %s", p.ts.ID(), err)
 	}
+
+	return err
 }
@@ -0,0 +1,249 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultVisibility is the time after which un-acked messages are redelivered if none is given.
+const DefaultVisibility = 30 * time.Second
+
+// NewAckTopicScanner returns a new persistent topic scanner starting at offset `from`
+// where messages are delivered at-least-once. Scanned messages not acked before
+// the visibility timeout are delivered again, the position persisted across
+// server restarts only moves forward when all previous messages are acked.
+func NewAckTopicScanner(t *Topic, ID string, from int64, visibility time.Duration) (*AckTopicScanner, error) {
+	bts, err := newBLTopicScanner(t, ID, from)
+	if err != nil {
+		return nil, ExtErr(err)
+	}
+
+	err = os.MkdirAll(t.readersDir(), 0755)
+	if err != nil {
+		log.Printf("error: %s", err)
+		logClose(bts)
+		return nil, ErrInvalidDir
+	}
+
+	fname := fmt.Sprintf(scannerPattern, ID)
+	fpath := filepath.Join(t.readersDir(), fname)
+
+	f, err := os.OpenFile(fpath, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		log.Printf("error: %s", err)
+		logClose(bts)
+		return nil, ErrInvalidDir
+	}
+
+	a := &AckTopicScanner{
+		f:          f,
+		fpath:      fpath,
+		ts:         bts,
+		visibility: visibility,
+		last:       from - 1,
+		acked:      from - 1,
+	}
+
+	if err = a.persist(); err != nil {
+		logClose(f)
+		logClose(bts)
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// AckTopicScanner wraps a scanner to deliver messages at-least-once,
+// consumers must ack every scanned message.
+type AckTopicScanner struct {
+	f          *os.File
+	fpath      string
+	ts         TopicScanner
+	visibility time.Duration
+
+	mu      sync.Mutex
+	pending []*pendingMessage // sorted by offset
+	last    int64             // last offset scanned from the underlying scanner
+	acked   int64             // every offset up to it is acked
+}
+
+type pendingMessage struct {
+	m        Message
+	offset   int64
+	deadline time.Time
+}
+
+// ID the ID of the scanner
+func (a *AckTopicScanner) ID() string {
+	return a.ts.ID()
+}
+
+// Scan returns the next message, either one whose visibility timeout expired
+// without being acked or a new one from the underlying scanner. Scan blocks
+// until any of them is available or the context is done.
+func (a *AckTopicScanner) Scan(ctx context.Context) (m Message, offset int64, err error) {
+	return scanOne(ctx, a)
+}
+
+// ScanN returns up to maxMessages messages or maxBytes bytes like Scan does.
+// Messages delivered again are never mixed with new ones in the same batch.
+func (a *AckTopicScanner) ScanN(ctx context.Context, maxMessages int, maxBytes int64) (msgs []Message, offsets []int64, err error) {
+	var wait time.Duration
+	for {
+		a.mu.Lock()
+		msgs, offsets, wait = a.redeliver(time.Now(), maxMessages, maxBytes)
+		a.mu.Unlock()
+
+		if len(msgs) > 0 {
+			return msgs, offsets, nil
+		}
+
+		// stop waiting for new data when the next redelivery is due
+		sctx, cancel := ctx, context.CancelFunc(func() {})
+		if wait > 0 {
+			sctx, cancel = context.WithTimeout(ctx, wait)
+		}
+
+		msgs, offsets, err = a.ts.ScanN(sctx, maxMessages, maxBytes)
+		cancel()
+
+		if err == ErrEndOfTopic && wait > 0 && ctx.Err() == nil {
+			continue
+		}
+
+		if err != nil {
+			return msgs, offsets, err
+		}
+
+		a.mu.Lock()
+		deadline := time.Now().Add(a.visibility)
+		for k, offset := range offsets {
+			a.pending = append(a.pending, &pendingMessage{
+				m:        msgs[k],
+				offset:   offset,
+				deadline: deadline,
+			})
+		}
+
+		a.last = offsets[len(offsets)-1]
+		a.mu.Unlock()
+
+		return msgs, offsets, nil
+	}
+}
+
+// redeliver returns the pending messages whose visibility timed out or otherwise
+// the time until the next one does, a.mu must be held.
+func (a *AckTopicScanner) redeliver(now time.Time, maxMessages int, maxBytes int64) (msgs []Message, offsets []int64, wait time.Duration) {
+	var size int64
+	for _, p := range a.pending {
+		if p.deadline.After(now) {
+			if d := p.deadline.Sub(now); wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+
+		if len(msgs) == maxMessages ||
+			len(msgs) > 0 && maxBytes > 0 && size+int64(len(p.m)) > maxBytes {
+			break
+		}
+
+		size += int64(len(p.m))
+		p.deadline = now.Add(a.visibility)
+		msgs = append(msgs, p.m)
+		offsets = append(offsets, p.offset)
+	}
+
+	return msgs, offsets, wait
+}
+
+// Ack acknowledges a scanned message so it's never delivered again. Acking
+// a message twice is allowed, ErrInvalidOffset is returned if it was never scanned.
+func (a *AckTopicScanner) Ack(offset int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if offset < 0 || offset > a.last {
+		return ErrInvalidOffset
+	}
+
+	for k, p := range a.pending {
+		if p.offset == offset {
+			a.pending = append(a.pending[:k], a.pending[k+1:]...)
+			break
+		}
+	}
+
+	acked := a.last
+	if len(a.pending) > 0 {
+		acked = a.pending[0].offset - 1
+	}
+
+	if acked == a.acked {
+		return nil
+	}
+
+	a.acked = acked
+	return a.persist()
+}
+
+// Info returns a TScannerInfo struct with the scanner's
+// next offset and the number of messages pending to be acked.
+func (a *AckTopicScanner) Info() TScannerInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := a.ts.Info()
+	i.Persist = true
+	i.Ack = true
+	i.Pending = len(a.pending)
+	return i
+}
+
+// Close deletes the offset tracking file and closes the underlying scanner
+func (a *AckTopicScanner) Close() error {
+	err := os.Remove(a.fpath)
+	if err != nil {
+		log.Printf("error: can't remove %s: %s", a.fpath, err)
+		return err
+	}
+
+	if err = a.f.Close(); err != nil {
+		return err
+	}
+
+	return a.ts.Close()
+}
+
+// release closes the underlying scanner keeping the offset tracking file, which
+// is written on every ack, so the scanner is restored on the next start.
+func (a *AckTopicScanner) release() error {
+	if err := a.f.Close(); err != nil {
+		return err
+	}
+
+	return a.ts.Close()
+}
+
+// persist writes the last acked offset followed by the visibility timeout.
+func (a *AckTopicScanner) persist() error {
+	buf := make([]byte, 16)
+	enc.PutUint64(buf[:8], uint64(a.acked))
+	enc.PutUint64(buf[8:], uint64(a.visibility))
+	_, err := a.f.WriteAt(buf, 0)
+	if err != nil {
+		log.Printf("error: failed to persist topic scanner %s: %s", a.ts.ID(), err)
+	}
+
+	return err
+}
@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package netlog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAckTopicScanner(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	messages := randMessageSet()
+	_, err = topic.WriteMessages(messages[:4])
+	panicOn(err)
+
+	ts, err := topic.NewAckScanner(0, 50*time.Millisecond)
+	panicOn(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	scan := func(expOffset int64) {
+		m, offset, err2 := ts.Scan(ctx)
+		panicOn(err2)
+		if offset != expOffset || !bytes.Equal(m, messages[offset]) {
+			t.Errorf("Scanned offset %d. Expected %d", offset, expOffset)
+		}
+	}
+
+	scan(0)
+	scan(1)
+	scan(2)
+	panicOn(topic.AckScanner(ts.ID(), 0))
+	panicOn(topic.AckScanner(ts.ID(), 2))
+
+	if info := ts.Info(); !info.Ack || info.Pending != 1 {
+		t.Errorf("Unexpected scanner info %+v", info)
+	}
+
+	// new messages come first, then the un-acked one once visible again
+	scan(3)
+	start := time.Now()
+	scan(1)
+	if time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Message redelivered before the visibility timeout")
+	}
+
+	panicOn(topic.AckScanner(ts.ID(), 1))
+	panicOn(topic.AckScanner(ts.ID(), 1))
+	if err = topic.AckScanner(ts.ID(), 10); err != ErrInvalidOffset {
+		t.Errorf("Expected ErrInvalidOffset, got %v", err)
+	}
+
+	// offset 3 was never acked and must be scanned again after a restart
+	nl2, err := NewNetLog(nl.dataDir)
+	panicOn(err)
+	topic2, err := nl2.Topic(topicName)
+	panicOn(err)
+	ts2, err := topic2.Scanner(ts.ID())
+	panicOn(err)
+
+	if info := ts2.Info(); !info.Ack || info.Next != 3 {
+		t.Errorf("Ack scanner not restored %+v", info)
+	}
+
+	panicOn(topic2.DeleteScanner(ts.ID()))
+
+	plain, err := topic.NewScanner(0, false)
+	panicOn(err)
+	if err = topic.AckScanner(plain.ID(), 0); err != ErrNotAckScanner {
+		t.Errorf("Expected ErrNotAckScanner, got %v", err)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
@@ -7,8 +7,12 @@ package netlog
 import (
 	"bytes"
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
+
+	"github.com/ninibe/bigduration"
 )
 
 func TestTopicScanner(t *testing.T) {
@@ -125,3 +129,272 @@ func TestScannerInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestScannerInfoWhileScanning(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	defer func() {
+		panicOn(nl.DeleteTopic(topicName, true))
+	}()
+
+	ts, err := topic.NewScanner(0, true)
+	panicOn(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scanned := make(chan error)
+	go func() {
+		_, _, err := ts.Scan(ctx)
+		scanned <- err
+	}()
+
+	// give the scan time to block waiting for messages
+	time.Sleep(50 * time.Millisecond)
+
+	infos := make(chan TScannerInfo)
+	go func() {
+		topic.ScannersInfo()
+		infos <- ts.Info()
+	}()
+
+	select {
+	case info := <-infos:
+		if info.Next != 0 {
+			t.Errorf("Unexpected next offset while scanning. Got: %d Exp: 0", info.Next)
+		}
+	case <-time.After(time.Second):
+		t.Error("Info blocked by a scan waiting for messages")
+	}
+
+	cancel()
+	if err = <-scanned; err != ErrEndOfTopic {
+		t.Errorf("Unexpected error of a cancelled scan: %v", err)
+	}
+}
+
+func TestTopicScannerScanN(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{CompressionType: CompressionGzip})
+	panicOn(err)
+
+	defer func() {
+		err = nl.DeleteTopic(topicName, true)
+		panicOn(err)
+	}()
+
+	messages := randMessageSet()
+	_, err = topic.WriteMessage(messages[0])
+	panicOn(err)
+	_, err = topic.WriteMessages(messages[1:6])
+	panicOn(err)
+
+	ts, err := topic.NewScanner(0, false)
+	panicOn(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	check := func(msgs []Message, offsets []int64, from int64, n int) {
+		if len(msgs) != n || len(offsets) != n {
+			t.Fatalf("Scanned %d messages. Expected %d", len(msgs), n)
+		}
+
+		for k, m := range msgs {
+			if offsets[k] != from+int64(k) || !bytes.Equal(m, messages[offsets[k]]) {
+				t.Errorf("Bad scan at offset %d", offsets[k])
+			}
+		}
+	}
+
+	// batches span across entries
+	msgs, offsets, err := ts.ScanN(ctx, 3, 0)
+	panicOn(err)
+	check(msgs, offsets, 0, 3)
+
+	// the byte limit keeps the rest for the next scan
+	limit := int64(len(messages[3]) + len(messages[4]))
+	msgs, offsets, err = ts.ScanN(ctx, 10, limit)
+	panicOn(err)
+	check(msgs, offsets, 3, 2)
+
+	// the first message is returned even if it exceeds the limit
+	msgs, offsets, err = ts.ScanN(ctx, 10, 1)
+	panicOn(err)
+	check(msgs, offsets, 5, 1)
+
+	cctx, ccancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer ccancel()
+	if _, _, err = ts.ScanN(cctx, 10, 0); err != ErrEndOfTopic {
+		t.Errorf("Expected ErrEndOfTopic, got %v", err)
+	}
+}
+
+func TestScannerLag(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	messages := randMessageSet()[:5]
+	for _, m := range messages {
+		_, err = topic.Write(m)
+		panicOn(err)
+	}
+
+	ts, err := topic.NewScanner(0, false)
+	panicOn(err)
+
+	info := ts.Info()
+	if info.Lag != 5 || !info.LastScan.IsZero() || info.Behind {
+		t.Errorf("Unexpected info of a new scanner %+v", info)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		_, _, err = ts.Scan(ctx)
+		panicOn(err)
+	}
+
+	var size int64
+	for _, m := range messages[2:] {
+		size += int64(len(m))
+	}
+
+	info = ts.Info()
+	if info.Lag != 3 || info.LagBytes != size || info.LastScan.IsZero() || info.IdleSeconds > 1 {
+		t.Errorf("Unexpected info after scanning %+v, expected lag of 3 messages and %d bytes", info, size)
+	}
+
+	if infos := topic.ScannersInfo(); len(infos) != 1 || infos[0].ID != ts.ID() {
+		t.Errorf("Unexpected scanners %+v", infos)
+	}
+
+	logClose(ts)
+	panicOn(nl.DeleteTopic(topicName, true))
+}
+
+func TestScannerBehind(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = topic.Write(MessageFromPayload(randData(10)))
+		panicOn(err)
+		if i < 2 {
+			panicOn(topic.bl.Split())
+		}
+	}
+
+	panicOn(topic.bl.Trim())
+	panicOn(topic.bl.Trim())
+
+	// a persisted scanner at offset 1, already discarded
+	ID := "00000000-0000-0000-0000-000000000001"
+	panicOn(os.MkdirAll(topic.readersDir(), 0755))
+	buf := make([]byte, 8)
+	enc.PutUint64(buf, 0)
+	panicOn(ioutil.WriteFile(topic.scannerPath(ID), buf, 0644))
+	topic.restorePersistedReaders()
+
+	ts, err := topic.Scanner(ID)
+	panicOn(err)
+	if info := ts.Info(); !info.Behind || info.Next != 2 || info.Lag != 1 {
+		t.Errorf("Unexpected info of a scanner behind %+v", info)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, o, err := ts.Scan(ctx)
+	panicOn(err)
+	if info := ts.Info(); o != 2 || info.Behind || info.Lag != 0 {
+		t.Errorf("Unexpected info after scanning offset %d %+v", o, info)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
+
+func TestScannerTTL(t *testing.T) {
+	t.Parallel()
+
+	nl := tempNetLog()
+	ScannerTTL(bigduration.BigDuration{Nanos: 100 * time.Millisecond})(nl)
+	topicName := randStr(6)
+	topic, err := nl.CreateTopic(topicName, TopicSettings{})
+	panicOn(err)
+
+	_, err = topic.Write(MessageFromPayload(randData(10)))
+	panicOn(err)
+
+	idle, err := topic.NewScanner(0, false)
+	panicOn(err)
+	active, err := topic.NewScanner(0, false)
+	panicOn(err)
+	persistent, err := topic.NewScanner(0, true)
+	panicOn(err)
+
+	if info := idle.Info(); info.TTL != 0.1 || info.ExpiresAt == nil {
+		t.Errorf("Unexpected info of a scanner with TTL %+v", info)
+	}
+
+	if info := persistent.Info(); info.TTL != 0 || info.ExpiresAt != nil {
+		t.Errorf("Unexpected TTL of a persistent scanner %+v", info)
+	}
+
+	// a scan blocked waiting for messages keeps the scanner alive
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _, err = active.Scan(ctx)
+	panicOn(err)
+	scanned := make(chan error)
+	go func() {
+		_, _, err := active.Scan(ctx)
+		scanned <- err
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	topic.expireScanners()
+
+	if _, err = topic.Scanner(idle.ID()); err != ErrScannerNotFound {
+		t.Errorf("Expected idle scanner to expire, got %v", err)
+	}
+
+	// scans racing with the expiration of the scanner fail
+	if _, _, err = idle.Scan(ctx); err != ErrScannerNotFound {
+		t.Errorf("Expected scan of expired scanner to fail with ErrScannerNotFound, got %v", err)
+	}
+
+	if _, err = topic.Scanner(active.ID()); err != nil {
+		t.Errorf("Scanner expired while scanning: %s", err)
+	}
+
+	if _, err = topic.Scanner(persistent.ID()); err != nil {
+		t.Errorf("Persistent scanner expired: %s", err)
+	}
+
+	_, err = topic.Write(MessageFromPayload(randData(10)))
+	panicOn(err)
+	panicOn(topic.FlushBuffered())
+	panicOn(<-scanned)
+
+	// the scan renewed the TTL
+	topic.expireScanners()
+	if _, err = topic.Scanner(active.ID()); err != nil {
+		t.Errorf("Scanner expired right after scanning: %s", err)
+	}
+
+	panicOn(nl.DeleteTopic(topicName, true))
+}
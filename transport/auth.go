@@ -0,0 +1,384 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ninibe/netlog"
+)
+
+// Right is a permission granted on topics.
+type Right string
+
+const (
+	// RightProduce allows writing messages into a topic and syncing it.
+	RightProduce Right = "produce"
+	// RightConsume allows reading, scanning, streaming and exporting a topic, and using its consumer groups.
+	RightConsume Right = "consume"
+	// RightAdmin allows creating, deleting, configuring, repairing and importing a topic,
+	// as well as deleting its consumer groups. It implies the rest of the rights.
+	RightAdmin Right = "admin"
+)
+
+// Grant gives a set of rights on the topics matched by Topic, which is either
+// a topic name or a prefix ending with "*". A single "*" matches every topic.
+type Grant struct {
+	Topic  string  `json:"topic"`
+	Rights []Right `json:"rights"`
+}
+
+// ACL maps every principal to the rights granted to it, unknown principals have no rights.
+type ACL map[string][]Grant
+
+// Allowed returns whether the principal has the right on the topic given.
+func (acl ACL) Allowed(principal, topic string, right Right) bool {
+	for _, g := range acl[principal] {
+		if !g.matches(topic) {
+			continue
+		}
+
+		for _, r := range g.Rights {
+			if r == right || r == RightAdmin {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (g Grant) matches(topic string) bool {
+	if strings.HasSuffix(g.Topic, "*") {
+		return strings.HasPrefix(topic, strings.TrimSuffix(g.Topic, "*"))
+	}
+
+	return g.Topic == topic
+}
+
+// validate checks that every right granted exists.
+func (acl ACL) validate() error {
+	for principal, grants := range acl {
+		for _, g := range grants {
+			for _, r := range g.Rights {
+				if r != RightProduce && r != RightConsume && r != RightAdmin {
+					return fmt.Errorf("unknown right %q granted to %q", r, principal)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Authenticator identifies the principal sending a request.
+type Authenticator interface {
+	// Authenticate returns the principal of the request or netlog.ErrUnauthorized
+	// if the request does not carry valid credentials for the authenticator.
+	Authenticate(r *http.Request) (principal string, err error)
+}
+
+// Authenticators tries each of its authenticators in order and
+// returns the principal of the first one accepting the request.
+type Authenticators []Authenticator
+
+// Authenticate implements the Authenticator interface.
+func (as Authenticators) Authenticate(r *http.Request) (string, error) {
+	for _, a := range as {
+		principal, err := a.Authenticate(r)
+		if err == nil {
+			return principal, nil
+		}
+	}
+
+	return "", netlog.ErrUnauthorized
+}
+
+// TokenAuth authenticates requests with static bearer tokens sent in the
+// Authorization header as "Bearer <token>". It maps principals to their tokens.
+type TokenAuth map[string]string
+
+// Authenticate implements the Authenticator interface.
+func (ta TokenAuth) Authenticate(r *http.Request) (string, error) {
+	token, ok := credentials(r, bearerScheme)
+	if !ok || token == "" {
+		return "", netlog.ErrUnauthorized
+	}
+
+	// compare every token in constant time
+	found := ""
+	for principal, t := range ta {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			found = principal
+		}
+	}
+
+	if found == "" {
+		return "", netlog.ErrUnauthorized
+	}
+
+	return found, nil
+}
+
+const (
+	bearerScheme = "Bearer"
+	hmacScheme   = "NetLog-HMAC"
+	// dateHeader carries the time a signed request was sent in RFC 3339.
+	dateHeader = "X-Netlog-Date"
+	// contentHashHeader carries the hex SHA-256 of the body of a signed request.
+	contentHashHeader = "X-Netlog-Content-SHA256"
+	// hmacMaxSkew is how far the date of a signed request can be from the
+	// time of the server, which limits the replay of captured requests.
+	hmacMaxSkew = 5 * time.Minute
+)
+
+// HMACAuth authenticates requests signed with SignRequest. It maps principals, used as
+// key IDs, to their secrets. Signatures cover the method, the host, the URI, the date
+// and the SHA-256 of the body of the request, which is read in memory to check it.
+type HMACAuth map[string]string
+
+// Authenticate implements the Authenticator interface.
+func (ha HMACAuth) Authenticate(r *http.Request) (string, error) {
+	cred, ok := credentials(r, hmacScheme)
+	if !ok {
+		return "", netlog.ErrUnauthorized
+	}
+
+	parts := strings.SplitN(cred, ":", 2)
+	if len(parts) != 2 {
+		return "", netlog.ErrUnauthorized
+	}
+
+	secret, ok := ha[parts[0]]
+	if !ok {
+		return "", netlog.ErrUnauthorized
+	}
+
+	date, err := time.Parse(time.RFC3339, r.Header.Get(dateHeader))
+	if err != nil {
+		return "", netlog.ErrUnauthorized
+	}
+
+	if skew := time.Since(date); skew > hmacMaxSkew || skew < -hmacMaxSkew {
+		return "", netlog.ErrUnauthorized
+	}
+
+	sig, err := hex.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, signature(r, secret)) {
+		return "", netlog.ErrUnauthorized
+	}
+
+	hash, err := hashBody(r)
+	if err != nil {
+		log.Printf("warn: failed to read body of signed request: %s", err)
+		return "", netlog.ErrUnauthorized
+	}
+
+	if !hmac.Equal([]byte(hash), []byte(r.Header.Get(contentHashHeader))) {
+		return "", netlog.ErrUnauthorized
+	}
+
+	return parts[0], nil
+}
+
+// SignRequest signs a request for HMACAuth with the key ID and secret given, setting its
+// date header to the current time if it was not set. The body is read to hash it and replaced.
+func SignRequest(r *http.Request, keyID, secret string) error {
+	if r.Header.Get(dateHeader) == "" {
+		r.Header.Set(dateHeader, time.Now().UTC().Format(time.RFC3339))
+	}
+
+	hash, err := hashBody(r)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(contentHashHeader, hash)
+	r.Header.Set("Authorization", hmacScheme+" "+keyID+":"+hex.EncodeToString(signature(r, secret)))
+	return nil
+}
+
+func signature(r *http.Request, secret string) []byte {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s", r.Method, host, r.URL.RequestURI(), r.Header.Get(dateHeader), r.Header.Get(contentHashHeader))
+	return mac.Sum(nil)
+}
+
+// hashBody returns the hex SHA-256 of the body of the request,
+// which is read in memory and replaced so it can be read again.
+func hashBody(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return "", err
+		}
+
+		logClose(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// credentials returns the credentials of the Authorization header if it uses the scheme given.
+func credentials(r *http.Request, scheme string) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(scheme) || !strings.EqualFold(auth[:len(scheme)], scheme) || auth[len(scheme)] != ' ' {
+		return "", false
+	}
+
+	return strings.TrimSpace(auth[len(scheme)+1:]), true
+}
+
+// AuthConfig is the configuration of the authentication and authorization of
+// the HTTP transport, usually read from a JSON file with ReadAuthConfig.
+type AuthConfig struct {
+	// Tokens maps principals to their bearer tokens.
+	Tokens map[string]string `json:"tokens"`
+	// HMACKeys maps principals to the secrets they sign requests with.
+	HMACKeys map[string]string `json:"hmac_keys"`
+	// ACL grants rights on topics to the principals.
+	ACL ACL `json:"acl"`
+}
+
+// ReadAuthConfig reads an AuthConfig from a JSON file.
+func ReadAuthConfig(path string) (*AuthConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer logClose(f)
+	config := &AuthConfig{}
+	if err = json.NewDecoder(f).Decode(config); err != nil {
+		return nil, err
+	}
+
+	if len(config.Tokens) == 0 && len(config.HMACKeys) == 0 {
+		return nil, errors.New("no tokens nor HMAC keys configured")
+	}
+
+	if err = config.ACL.validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Authenticator returns the authenticator of the tokens and HMAC keys configured.
+func (c *AuthConfig) Authenticator() Authenticator {
+	return Authenticators{TokenAuth(c.Tokens), HMACAuth(c.HMACKeys)}
+}
+
+// HTTPOption is the type of function used to set up an HTTPTransport.
+type HTTPOption func(*HTTPTransport)
+
+// Auth requires every request to be authenticated by `auth` and authorized by `acl`.
+// Requests without valid credentials get ErrUnauthorized and the ones of principals
+// lacking the right needed on the topic get ErrForbidden.
+func Auth(auth Authenticator, acl ACL) HTTPOption {
+	return func(ht *HTTPTransport) {
+		ht.auth = auth
+		ht.acl = acl
+	}
+}
+
+type principalKey struct{}
+
+// authenticate returns the request with its principal in the context, or
+// writes ErrUnauthorized and returns nil if it could not be authenticated.
+func (ht *HTTPTransport) authenticate(w http.ResponseWriter, r *http.Request) *http.Request {
+	if ht.auth == nil {
+		return r
+	}
+
+	principal, err := ht.auth.Authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", bearerScheme+` realm="netlog"`)
+		JSONErrorResponse(w, err)
+		return nil
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+}
+
+// Protect wraps a handler served outside of the transport, like the metrics or the replication
+// ones, so it requires the same authentication and the right on every topic, granted with "*".
+func (ht *HTTPTransport) Protect(h http.Handler, right Right) http.Handler {
+	if ht.auth == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r = ht.authenticate(w, r); r == nil {
+			return
+		}
+
+		ht.allow(right, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			h.ServeHTTP(w, r)
+		})(w, r, nil)
+	})
+}
+
+// visibleTopics filters out the topics the principal of the request has no rights on.
+func (ht *HTTPTransport) visibleTopics(r *http.Request, topics []string) []string {
+	if ht.auth == nil {
+		return topics
+	}
+
+	principal, _ := r.Context().Value(principalKey{}).(string)
+	visible := make([]string, 0, len(topics))
+	for _, name := range topics {
+		if ht.acl.Allowed(principal, name, RightConsume) || ht.acl.Allowed(principal, name, RightProduce) {
+			visible = append(visible, name)
+		}
+	}
+
+	return visible
+}
+
+// allow wraps a handler rejecting with ErrForbidden the requests
+// whose principal lacks `right` on the topic of the route.
+func (ht *HTTPTransport) allow(right Right, h httprouter.Handle) httprouter.Handle {
+	if ht.auth == nil {
+		return h
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		principal, _ := r.Context().Value(principalKey{}).(string)
+		if !ht.acl.Allowed(principal, ps.ByName("topic"), right) {
+			JSONErrorResponse(w, netlog.ErrForbidden)
+			return
+		}
+
+		h(w, r, ps)
+	}
+}
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuth(t *testing.T) {
+	acl := ACL{
+		"admin":    {{Topic: "*", Rights: []Right{RightAdmin}}},
+		"producer": {{Topic: "billing*", Rights: []Right{RightProduce}}},
+		"reader":   {{Topic: "billing", Rights: []Right{RightConsume}}},
+	}
+
+	auth := Authenticators{
+		TokenAuth{"admin": "admin-token", "producer": "producer-token"},
+		HMACAuth{"reader": "reader-secret"},
+	}
+
+	ts := runTestHTTPServer(Auth(auth, acl))
+	do := func(method, path, body string, sign func(r *http.Request)) int {
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		panicOn(err)
+		if sign != nil {
+			sign(req)
+		}
+
+		r, err := http.DefaultClient.Do(req)
+		panicOn(err)
+		logClose(r.Body)
+		return r.StatusCode
+	}
+
+	token := func(tok string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+tok) }
+	}
+
+	hmacKey := func(secret string) func(r *http.Request) {
+		return func(r *http.Request) { panicOn(SignRequest(r, "reader", secret)) }
+	}
+
+	cases := []struct {
+		method, path string
+		sign         func(r *http.Request)
+		status       int
+	}{
+		{"POST", "/billing", nil, http.StatusUnauthorized},
+		{"POST", "/billing", token("wrong"), http.StatusUnauthorized},
+		{"POST", "/billing", token("producer-token"), http.StatusForbidden},
+		{"POST", "/billing", token("admin-token"), http.StatusCreated},
+		{"POST", "/other", token("admin-token"), http.StatusCreated},
+		{"POST", "/billing/payload", token("producer-token"), http.StatusCreated},
+		{"POST", "/other/payload", token("producer-token"), http.StatusForbidden},
+		{"GET", "/billing/payload/0", token("producer-token"), http.StatusForbidden},
+		{"GET", "/billing/payload/0", hmacKey("reader-secret"), http.StatusOK},
+		{"GET", "/billing/payload/0", hmacKey("wrong"), http.StatusUnauthorized},
+		{"POST", "/billing/payload", hmacKey("reader-secret"), http.StatusForbidden},
+		{"DELETE", "/billing", token("producer-token"), http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		if status := do(c.method, c.path, "{}", c.sign); status != c.status {
+			t.Errorf("%s %s: expected status %d, got %d", c.method, c.path, c.status, status)
+		}
+	}
+
+	// signatures expire
+	stale := func(r *http.Request) {
+		r.Header.Set(dateHeader, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+		panicOn(SignRequest(r, "reader", "reader-secret"))
+	}
+
+	if status := do("GET", "/billing/payload/0", "", stale); status != http.StatusUnauthorized {
+		t.Errorf("Expected stale signature to be rejected, got %d", status)
+	}
+
+	// signatures cover the body and the host
+	tampered := func(r *http.Request) {
+		panicOn(SignRequest(r, "reader", "reader-secret"))
+		r.Body = ioutil.NopCloser(strings.NewReader(`{"tampered": true}`))
+		r.ContentLength = -1
+	}
+
+	if status := do("POST", "/billing/payload", "{}", tampered); status != http.StatusUnauthorized {
+		t.Errorf("Expected tampered body to be rejected, got %d", status)
+	}
+
+	rehosted := func(r *http.Request) {
+		panicOn(SignRequest(r, "reader", "reader-secret"))
+		r.Host = "other.example.com"
+	}
+
+	if status := do("GET", "/billing/payload/0", "", rehosted); status != http.StatusUnauthorized {
+		t.Errorf("Expected signature for another host to be rejected, got %d", status)
+	}
+
+	// only the topics with rights are listed
+	req, err := http.NewRequest("GET", ts.URL+"/", nil)
+	panicOn(err)
+	token("producer-token")(req)
+	r, err := http.DefaultClient.Do(req)
+	panicOn(err)
+	var topics []string
+	panicOn(json.NewDecoder(r.Body).Decode(&topics))
+	logClose(r.Body)
+	if len(topics) != 1 || topics[0] != "billing" {
+		t.Errorf("Unexpected topics listed %v", topics)
+	}
+
+	if r.Header.Get("WWW-Authenticate") != "" {
+		t.Errorf("Unexpected WWW-Authenticate header on an authenticated request")
+	}
+}
+
+func TestReadAuthConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netlog-auth")
+	panicOn(err)
+	defer func() { panicOn(os.RemoveAll(dir)) }()
+
+	path := filepath.Join(dir, "auth.json")
+	panicOn(ioutil.WriteFile(path, []byte(`{
+		"tokens": {"admin": "admin-token"},
+		"acl": {"admin": [{"topic": "*", "rights": ["admin"]}]}
+	}`), 0600))
+
+	config, err := ReadAuthConfig(path)
+	panicOn(err)
+	if !config.ACL.Allowed("admin", "any", RightProduce) {
+		t.Errorf("Expected admin right to imply produce")
+	}
+
+	panicOn(ioutil.WriteFile(path, []byte(`{
+		"tokens": {"admin": "admin-token"},
+		"acl": {"admin": [{"topic": "*", "rights": ["write"]}]}
+	}`), 0600))
+
+	if _, err = ReadAuthConfig(path); err == nil {
+		t.Errorf("Expected error reading an unknown right")
+	}
+}
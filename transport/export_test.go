@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/ninibe/netlog"
+)
+
+func TestExportImportTopic(t *testing.T) {
+	ts := runTestHTTPServer()
+	topicURL := fmt.Sprintf("%s/export_test", ts.URL)
+
+	r, err := http.Post(topicURL, "application/json", bytes.NewBufferString(`{"compression_type": 3}`))
+	panicOn(err)
+	logClose(r.Body)
+
+	for i := 0; i < 5; i++ {
+		r, err = http.Post(topicURL+"/payload", "", bytes.NewReader(randData(20)))
+		panicOn(err)
+		logClose(r.Body)
+	}
+
+	r, err = http.Get(topicURL + "/export?from=2&to=3")
+	panicOn(err)
+	export, err := ioutil.ReadAll(r.Body)
+	panicOn(err)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusOK || r.Trailer.Get("X-error") != "" {
+		t.Fatalf("Export failed %d %s", r.StatusCode, r.Trailer.Get("X-error"))
+	}
+
+	r, err = http.Post(ts.URL+"/imported/import?preserve_offsets=true", "application/gzip", bytes.NewReader(export))
+	panicOn(err)
+	var info netlog.TopicInfo
+	panicOn(json.NewDecoder(r.Body).Decode(&info))
+	logClose(r.Body)
+	if r.StatusCode != http.StatusCreated || info.FirstOffset != 2 || info.LatestOffset != 3 {
+		t.Errorf("Unexpected import response %d %+v", r.StatusCode, info)
+	}
+
+	r, err = http.Post(ts.URL+"/imported/import", "application/gzip", bytes.NewReader(export))
+	panicOn(err)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 importing an existing topic, got %d", r.StatusCode)
+	}
+}
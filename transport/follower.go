@@ -0,0 +1,341 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ninibe/netlog"
+)
+
+// NewFollower returns a Follower replicating every topic of the NetLog served
+// by the HTTP transport at `leader` into `nl`, which is switched to read-only.
+// The leader is polled for new topics and lag every `interval`.
+func NewFollower(nl *netlog.NetLog, leader string, interval time.Duration) *Follower {
+	nl.SetReadOnly(true)
+	return &Follower{
+		nl:       nl,
+		leader:   strings.TrimRight(leader, "/"),
+		interval: interval,
+		client:   &http.Client{},
+		topics:   make(map[string]*followedTopic),
+		promote:  make(chan struct{}),
+	}
+}
+
+// Follower asynchronously replicates the topics of a leader keeping their offsets.
+// Stored entries are copied byte-for-byte keeping their offsets and boundaries,
+// so message-sets and sequences of messages are replicated as they are.
+type Follower struct {
+	nl       *netlog.NetLog
+	leader   string
+	interval time.Duration
+	client   *http.Client
+	token    string
+
+	mu       sync.Mutex
+	topics   map[string]*followedTopic
+	promote  chan struct{}
+	promoted bool
+}
+
+type followedTopic struct {
+	topic  *netlog.Topic
+	latest int64 // latest offset in the leader
+	cancel context.CancelFunc
+}
+
+// ReplicationInfo holds the replication state of a follower.
+type ReplicationInfo struct {
+	Leader   string                    `json:"leader"`
+	Promoted bool                      `json:"promoted"`
+	Topics   map[string]ReplicaLagInfo `json:"topics"`
+}
+
+// ReplicaLagInfo holds the replication lag of a topic.
+type ReplicaLagInfo struct {
+	LeaderOffset int64 `json:"leader_offset"`
+	Offset       int64 `json:"offset"`
+	Lag          int64 `json:"lag"`
+}
+
+// Run replicates the leader until the context is cancelled or the follower is promoted.
+func (f *Follower) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		err := f.sync(ctx)
+		if err != nil {
+			log.Printf("warn: failed to sync with leader %s: %s", f.leader, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-f.promote:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync starts following any new topic in the leader and updates the lag of the known ones.
+func (f *Follower) sync(ctx context.Context) error {
+	var names []string
+	err := f.getJSON(ctx, "/", &names)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		var info netlog.TopicInfo
+		err = f.getJSON(ctx, "/"+url.PathEscape(name), &info)
+		if err != nil {
+			log.Printf("warn: failed to fetch topic %q from leader: %s", name, err)
+			continue
+		}
+
+		f.mu.Lock()
+		ft, ok := f.topics[name]
+		if !ok && !f.promoted {
+			ft, err = f.follow(ctx, name, &info)
+		}
+		f.mu.Unlock()
+
+		if err != nil {
+			log.Printf("error: failed to follow topic %q: %s", name, err)
+			continue
+		}
+
+		if ft != nil {
+			ft.setLatest(info.LatestOffset)
+		}
+	}
+
+	return nil
+}
+
+// follow creates the local replica of a topic if needed and starts tailing it, f.mu must be held.
+func (f *Follower) follow(ctx context.Context, name string, info *netlog.TopicInfo) (*followedTopic, error) {
+	t, err := f.nl.Topic(name)
+	if err == netlog.ErrTopicNotFound {
+		log.Printf("info: replicating new topic %q from offset %d", name, info.FirstOffset)
+		t, err = f.nl.CreateReplica(name, info.Settings, info.FirstOffset)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	tctx, cancel := context.WithCancel(ctx)
+	ft := &followedTopic{topic: t, latest: info.LatestOffset, cancel: cancel}
+	f.topics[name] = ft
+
+	go f.tail(tctx, ft)
+	return ft, nil
+}
+
+// tail streams the topic from the leader until the context is cancelled, retrying on errors.
+func (f *Follower) tail(ctx context.Context, ft *followedTopic) {
+	for {
+		err := f.stream(ctx, ft)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("warn: replication of %q interrupted: %s", ft.topic.Name(), err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+func (f *Follower) stream(ctx context.Context, ft *followedTopic) error {
+	t := ft.topic
+	next := t.Latest() + 1
+
+	u := fmt.Sprintf("%s/%s/stream?from=%d&entries=true", f.leader, url.PathEscape(t.Name()), next)
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := f.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	defer logClose(res.Body)
+	if res.StatusCode != http.StatusOK {
+		return leaderError(res)
+	}
+
+	r := bufio.NewReader(res.Body)
+	for {
+		h, err := netlog.ReadDeltaHeader(r)
+		if err == io.EOF && res.Trailer.Get("X-error") != "" {
+			return fmt.Errorf("leader: %s", res.Trailer.Get("X-error"))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if h.Offset != next {
+			return netlog.ErrReplicaDiverged
+		}
+
+		// every delta holds a single entry, offsets removed
+		// by compaction on the leader come without data
+		var entry []byte
+		if h.Size > 0 {
+			entry = make([]byte, h.Size)
+			_, err = io.ReadFull(r, entry)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = t.Replicate(next, entry, int(h.ODelta))
+		if err != nil {
+			return err
+		}
+
+		next += h.ODelta
+		ft.setLatest(next - 1)
+	}
+}
+
+// Info returns the replication state and the lag of every replicated topic.
+func (f *Follower) Info() *ReplicationInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info := &ReplicationInfo{
+		Leader:   f.leader,
+		Promoted: f.promoted,
+		Topics:   make(map[string]ReplicaLagInfo, len(f.topics)),
+	}
+
+	for name, ft := range f.topics {
+		latest := atomic.LoadInt64(&ft.latest)
+		offset := ft.topic.Latest()
+		lag := latest - offset
+		if lag < 0 {
+			lag = 0
+		}
+
+		info.Topics[name] = ReplicaLagInfo{
+			LeaderOffset: latest,
+			Offset:       offset,
+			Lag:          lag,
+		}
+	}
+
+	return info
+}
+
+// Promote stops replicating and turns the NetLog into a writable leader.
+func (f *Follower) Promote() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.promoted {
+		return
+	}
+
+	log.Printf("info: promoting follower of %s to leader", f.leader)
+	f.promoted = true
+	close(f.promote)
+	for _, ft := range f.topics {
+		ft.cancel()
+	}
+
+	f.nl.SetReadOnly(false)
+}
+
+// ServeHTTP returns the replication state on GET requests
+// and promotes the follower to leader on POST requests.
+func (f *Follower) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		JSONResponse(w, f.Info())
+	case "POST":
+		f.Promote()
+		JSONOKResponse(w, "promoted to leader")
+	default:
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+	}
+}
+
+// UseToken authenticates the requests to the leader with a bearer token,
+// whose principal needs the consume right on every topic.
+func (f *Follower) UseToken(token string) {
+	f.token = token
+}
+
+// do sends a request to the leader.
+func (f *Follower) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if f.token != "" {
+		req.Header.Set("Authorization", bearerScheme+" "+f.token)
+	}
+
+	return f.client.Do(req.WithContext(ctx))
+}
+
+func (f *Follower) getJSON(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequest("GET", f.leader+path, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := f.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	defer logClose(res.Body)
+	if res.StatusCode != http.StatusOK {
+		return leaderError(res)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func leaderError(res *http.Response) error {
+	var e struct {
+		Err string `json:"error"`
+	}
+
+	_ = json.NewDecoder(res.Body).Decode(&e)
+	return fmt.Errorf("leader: status %d %s", res.StatusCode, e.Err)
+}
+
+func (ft *followedTopic) setLatest(offset int64) {
+	for {
+		latest := atomic.LoadInt64(&ft.latest)
+		if offset <= latest || atomic.CompareAndSwapInt64(&ft.latest, latest, offset) {
+			return
+		}
+	}
+}
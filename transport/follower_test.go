@@ -0,0 +1,212 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog"
+)
+
+func tempNetLog() *netlog.NetLog {
+	datadir := filepath.Join(os.TempDir(), fmt.Sprintf("netlogtest-%d", rand.Int63()))
+	panicOn(os.Mkdir(datadir, 0755))
+
+	bd, err := bigduration.ParseBigDuration("1day")
+	panicOn(err)
+	nl, err := netlog.NewNetLog(datadir, netlog.MonitorInterval(bd))
+	panicOn(err)
+
+	return nl
+}
+
+func TestFollower(t *testing.T) {
+	leader := tempNetLog()
+	ts := httptest.NewServer(NewHTTPTransport(leader))
+	defer ts.Close()
+
+	bi, err := bigduration.ParseBigDuration("1h")
+	panicOn(err)
+
+	plain, err := leader.CreateTopic("plain", netlog.TopicSettings{})
+	panicOn(err)
+	batched, err := leader.CreateTopic("batched", netlog.TopicSettings{
+		BatchNumMessages: 3,
+		BatchInterval:    bi,
+		CompressionType:  netlog.CompressionGzip,
+	})
+	panicOn(err)
+
+	write := func(data [][]byte) {
+		for _, d := range data {
+			_, err2 := plain.Write(netlog.MessageFromPayload(d))
+			panicOn(err2)
+			_, err2 = batched.Write(netlog.MessageFromPayload(d))
+			panicOn(err2)
+		}
+		panicOn(batched.Sync())
+	}
+
+	data := randDataSet(20, 256)
+	write(data[:10])
+
+	nl := tempNetLog()
+	f := NewFollower(nl, ts.URL, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	go func() { _ = f.Run(ctx) }()
+
+	// keeps replicating new data
+	write(data[10:])
+	waitForSync(t, f, "plain", plain.Latest())
+	waitForSync(t, f, "batched", batched.Latest())
+
+	for _, lt := range []*netlog.Topic{plain, batched} {
+		ft, err2 := nl.Topic(lt.Name())
+		panicOn(err2)
+
+		info, err2 := ft.Info()
+		panicOn(err2)
+		if info.Settings.CompressionType != topicSettings(lt).CompressionType {
+			t.Errorf("Topic %q created with different settings", lt.Name())
+		}
+
+		if raw := streamAll(ft); len(raw) == 0 || !bytes.Equal(raw, streamAll(lt)) {
+			t.Errorf("Replica of %q is not equal to the original", lt.Name())
+		}
+
+		for k := range data {
+			p, err3 := ft.Payload(int64(k))
+			panicOn(err3)
+			if !bytes.Equal(p, data[k]) {
+				t.Errorf("Bad replicated payload at %s:%d", lt.Name(), k)
+			}
+		}
+	}
+
+	ft, err := nl.Topic("plain")
+	panicOn(err)
+	if _, err = ft.Write(netlog.MessageFromPayload(data[0])); err != netlog.ErrReadOnly {
+		t.Errorf("Expected ErrReadOnly writing on follower, got %v", err)
+	}
+
+	f.Promote()
+	if _, err = ft.Write(netlog.MessageFromPayload(data[0])); err != nil {
+		t.Errorf("Failed to write after promotion %s", err)
+	}
+
+	if !f.Info().Promoted {
+		t.Errorf("Follower not marked as promoted")
+	}
+}
+
+func TestFollowerEntries(t *testing.T) {
+	leader := tempNetLog()
+	ts := httptest.NewServer(NewHTTPTransport(leader))
+	defer ts.Close()
+
+	lt, err := leader.CreateTopic("entries", netlog.TopicSettings{})
+	panicOn(err)
+
+	data := randDataSet(10, 128)
+	_, err = lt.Write(netlog.MessageFromPayload(data[0]))
+	panicOn(err)
+
+	// a batch of payloads is stored as a single message-set
+	framed := &bytes.Buffer{}
+	for _, d := range data[1:6] {
+		framed.Write(netlog.MessageFromPayload(d))
+	}
+
+	res, err := http.Post(ts.URL+"/entries/payloads", "application/octet-stream", framed)
+	panicOn(err)
+	logClose(res.Body)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("Unexpected status writing payloads %d", res.StatusCode)
+	}
+
+	// a sequence of messages stored as a single entry
+	seq := &bytes.Buffer{}
+	for _, d := range data[6:9] {
+		seq.Write(netlog.MessageFromPayload(d))
+	}
+
+	_, err = lt.WriteN(seq.Bytes(), 3)
+	panicOn(err)
+	_, err = lt.Write(netlog.MessageFromPayload(data[9]))
+	panicOn(err)
+
+	nl := tempNetLog()
+	f := NewFollower(nl, ts.URL, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	go func() { _ = f.Run(ctx) }()
+
+	waitForSync(t, f, "entries", lt.Latest())
+
+	ft, err := nl.Topic("entries")
+	panicOn(err)
+
+	if raw := streamAll(ft); len(raw) == 0 || !bytes.Equal(raw, streamAll(lt)) {
+		t.Errorf("Replica entries are not equal to the original ones")
+	}
+
+	for k := range data {
+		p, err2 := ft.Payload(int64(k))
+		panicOn(err2)
+		if !bytes.Equal(p, data[k]) {
+			t.Errorf("Bad replicated payload at %d", k)
+		}
+	}
+}
+
+func waitForSync(t *testing.T, f *Follower, name string, latest int64) {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if lag, ok := f.Info().Topics[name]; ok && lag.Offset == latest && lag.Lag == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Follower did not catch up on %q: %+v", name, f.Info().Topics[name])
+}
+
+// streamAll returns all raw data stored in the topic one entry per delta,
+// so topics with the same data in different entries are not equal
+func streamAll(t *netlog.Topic) []byte {
+	st, err := t.NewStreamer(0)
+	panicOn(err)
+	defer logClose(st)
+
+	buf := &bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for {
+		if _, err = st.NextEntry(ctx, buf, 1000, 1024*1024); err != nil {
+			break
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func topicSettings(t *netlog.Topic) netlog.TopicSettings {
+	info, err := t.Info()
+	panicOn(err)
+	return info.Settings
+}
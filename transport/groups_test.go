@@ -0,0 +1,111 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ninibe/netlog"
+)
+
+func TestConsumerGroups(t *testing.T) {
+	ts := runTestHTTPServer()
+	topicURL := fmt.Sprintf("%s/group_test", ts.URL)
+	groupURL := topicURL + "/groups/workers"
+
+	do := func(method, url string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, url, bytes.NewReader(body))
+		panicOn(err)
+		r, err := http.DefaultClient.Do(req)
+		panicOn(err)
+		return r
+	}
+
+	r := do("POST", topicURL, nil)
+	logClose(r.Body)
+
+	data := randDataSet(5, 128)
+	for _, d := range data {
+		r = do("POST", topicURL+"/payload", d)
+		logClose(r.Body)
+	}
+
+	join := func() string {
+		r2 := do("POST", groupURL+"/join?from=0", nil)
+		defer logClose(r2.Body)
+		if r2.StatusCode != http.StatusCreated {
+			t.Fatalf("Failed to join group: %d", r2.StatusCode)
+		}
+
+		var msg IDMsg
+		panicOn(json.NewDecoder(r2.Body).Decode(&msg))
+		return msg.ID
+	}
+
+	fetch := func(member string, max int) (string, []netlog.Message) {
+		r2 := do("GET", fmt.Sprintf("%s/fetch?member=%s&max=%d", groupURL, member, max), nil)
+		defer logClose(r2.Body)
+		if r2.StatusCode != http.StatusOK {
+			return "", nil
+		}
+
+		offsets, msgs := readMessages(r2.Body)
+		if len(offsets) == 0 {
+			return "", msgs
+		}
+
+		return fmt.Sprint(offsets[0]), msgs
+	}
+
+	m1, m2 := join(), join()
+	offset, msgs := fetch(m1, 3)
+	if offset != "0" || len(msgs) != 3 || !bytes.Equal(msgs[2].Payload(), data[2]) {
+		t.Errorf("Unexpected fetch from %s of %d messages", offset, len(msgs))
+	}
+
+	offset, msgs = fetch(m2, 3)
+	if offset != "3" || len(msgs) != 2 || !bytes.Equal(msgs[1].Payload(), data[4]) {
+		t.Errorf("Unexpected fetch from %s of %d messages", offset, len(msgs))
+	}
+
+	r = do("POST", fmt.Sprintf("%s/commit?member=%s&offset=2", groupURL, m1), nil)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusOK {
+		t.Errorf("Failed to commit: %d", r.StatusCode)
+	}
+
+	r = do("GET", groupURL, nil)
+	var info netlog.GroupInfo
+	panicOn(json.NewDecoder(r.Body).Decode(&info))
+	logClose(r.Body)
+	if info.Committed != 3 || len(info.Members) != 2 {
+		t.Errorf("Unexpected group info %+v", info)
+	}
+
+	r = do("POST", groupURL+"/leave?member="+m2, nil)
+	logClose(r.Body)
+
+	// released offsets are fetched again
+	offset, msgs = fetch(m1, 5)
+	if offset != "3" || len(msgs) != 2 {
+		t.Errorf("Unexpected fetch from %s of %d messages", offset, len(msgs))
+	}
+
+	r = do("DELETE", groupURL, nil)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusOK {
+		t.Errorf("Failed to delete group: %d", r.StatusCode)
+	}
+
+	r = do("GET", groupURL, nil)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusNotFound {
+		t.Errorf("Group not deleted: %d", r.StatusCode)
+	}
+}
@@ -0,0 +1,655 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: netlog.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NetLog_ServerInfo_FullMethodName    = "/netlog.NetLog/ServerInfo"
+	NetLog_CreateTopic_FullMethodName   = "/netlog.NetLog/CreateTopic"
+	NetLog_DeleteTopic_FullMethodName   = "/netlog.NetLog/DeleteTopic"
+	NetLog_TopicInfo_FullMethodName     = "/netlog.NetLog/TopicInfo"
+	NetLog_WritePayload_FullMethodName  = "/netlog.NetLog/WritePayload"
+	NetLog_WritePayloads_FullMethodName = "/netlog.NetLog/WritePayloads"
+	NetLog_ReadPayload_FullMethodName   = "/netlog.NetLog/ReadPayload"
+	NetLog_CreateScanner_FullMethodName = "/netlog.NetLog/CreateScanner"
+	NetLog_DeleteScanner_FullMethodName = "/netlog.NetLog/DeleteScanner"
+	NetLog_AckScanner_FullMethodName    = "/netlog.NetLog/AckScanner"
+	NetLog_Scan_FullMethodName          = "/netlog.NetLog/Scan"
+	NetLog_Sync_FullMethodName          = "/netlog.NetLog/Sync"
+	NetLog_Check_FullMethodName         = "/netlog.NetLog/Check"
+	NetLog_Subscribe_FullMethodName     = "/netlog.NetLog/Subscribe"
+)
+
+// NetLogClient is the client API for NetLog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NetLog exposes the same operations as the HTTP transport.
+type NetLogClient interface {
+	// ServerInfo returns the list of existing topics.
+	ServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error)
+	// CreateTopic creates a new topic, zero settings fall back to the server defaults.
+	CreateTopic(ctx context.Context, in *CreateTopicRequest, opts ...grpc.CallOption) (*CreateTopicResponse, error)
+	// DeleteTopic deletes an existing topic.
+	DeleteTopic(ctx context.Context, in *DeleteTopicRequest, opts ...grpc.CallOption) (*DeleteTopicResponse, error)
+	// TopicInfo returns the information of a topic.
+	TopicInfo(ctx context.Context, in *TopicInfoRequest, opts ...grpc.CallOption) (*TopicInfoResponse, error)
+	// WritePayload writes a single payload into a topic.
+	WritePayload(ctx context.Context, in *WritePayloadRequest, opts ...grpc.CallOption) (*WritePayloadResponse, error)
+	// WritePayloads writes a list of payloads into a topic.
+	WritePayloads(ctx context.Context, in *WritePayloadsRequest, opts ...grpc.CallOption) (*WritePayloadsResponse, error)
+	// ReadPayload reads the payload stored at a given offset.
+	ReadPayload(ctx context.Context, in *ReadPayloadRequest, opts ...grpc.CallOption) (*Record, error)
+	// CreateScanner creates a new scanner on a topic.
+	CreateScanner(ctx context.Context, in *CreateScannerRequest, opts ...grpc.CallOption) (*ScannerInfo, error)
+	// DeleteScanner deletes an existing scanner.
+	DeleteScanner(ctx context.Context, in *DeleteScannerRequest, opts ...grpc.CallOption) (*DeleteScannerResponse, error)
+	// AckScanner acknowledges a message scanned by a scanner in ack mode.
+	AckScanner(ctx context.Context, in *AckScannerRequest, opts ...grpc.CallOption) (*AckScannerResponse, error)
+	// Scan returns the next message of a scanner, waiting for it if requested.
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*Record, error)
+	// Sync flushes all topic data to disk.
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+	// Check checks the integrity of a topic.
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	// Subscribe streams all messages of a topic as they get written.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error)
+}
+
+type netLogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNetLogClient(cc grpc.ClientConnInterface) NetLogClient {
+	return &netLogClient{cc}
+}
+
+func (c *netLogClient) ServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfoResponse)
+	err := c.cc.Invoke(ctx, NetLog_ServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) CreateTopic(ctx context.Context, in *CreateTopicRequest, opts ...grpc.CallOption) (*CreateTopicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTopicResponse)
+	err := c.cc.Invoke(ctx, NetLog_CreateTopic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) DeleteTopic(ctx context.Context, in *DeleteTopicRequest, opts ...grpc.CallOption) (*DeleteTopicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTopicResponse)
+	err := c.cc.Invoke(ctx, NetLog_DeleteTopic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) TopicInfo(ctx context.Context, in *TopicInfoRequest, opts ...grpc.CallOption) (*TopicInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopicInfoResponse)
+	err := c.cc.Invoke(ctx, NetLog_TopicInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) WritePayload(ctx context.Context, in *WritePayloadRequest, opts ...grpc.CallOption) (*WritePayloadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WritePayloadResponse)
+	err := c.cc.Invoke(ctx, NetLog_WritePayload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) WritePayloads(ctx context.Context, in *WritePayloadsRequest, opts ...grpc.CallOption) (*WritePayloadsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WritePayloadsResponse)
+	err := c.cc.Invoke(ctx, NetLog_WritePayloads_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) ReadPayload(ctx context.Context, in *ReadPayloadRequest, opts ...grpc.CallOption) (*Record, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Record)
+	err := c.cc.Invoke(ctx, NetLog_ReadPayload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) CreateScanner(ctx context.Context, in *CreateScannerRequest, opts ...grpc.CallOption) (*ScannerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScannerInfo)
+	err := c.cc.Invoke(ctx, NetLog_CreateScanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) DeleteScanner(ctx context.Context, in *DeleteScannerRequest, opts ...grpc.CallOption) (*DeleteScannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScannerResponse)
+	err := c.cc.Invoke(ctx, NetLog_DeleteScanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) AckScanner(ctx context.Context, in *AckScannerRequest, opts ...grpc.CallOption) (*AckScannerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AckScannerResponse)
+	err := c.cc.Invoke(ctx, NetLog_AckScanner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*Record, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Record)
+	err := c.cc.Invoke(ctx, NetLog_Scan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncResponse)
+	err := c.cc.Invoke(ctx, NetLog_Sync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckResponse)
+	err := c.cc.Invoke(ctx, NetLog_Check_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netLogClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NetLog_ServiceDesc.Streams[0], NetLog_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, SubscribeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetLog_SubscribeClient = grpc.ServerStreamingClient[SubscribeResponse]
+
+// NetLogServer is the server API for NetLog service.
+// All implementations must embed UnimplementedNetLogServer
+// for forward compatibility.
+//
+// NetLog exposes the same operations as the HTTP transport.
+type NetLogServer interface {
+	// ServerInfo returns the list of existing topics.
+	ServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error)
+	// CreateTopic creates a new topic, zero settings fall back to the server defaults.
+	CreateTopic(context.Context, *CreateTopicRequest) (*CreateTopicResponse, error)
+	// DeleteTopic deletes an existing topic.
+	DeleteTopic(context.Context, *DeleteTopicRequest) (*DeleteTopicResponse, error)
+	// TopicInfo returns the information of a topic.
+	TopicInfo(context.Context, *TopicInfoRequest) (*TopicInfoResponse, error)
+	// WritePayload writes a single payload into a topic.
+	WritePayload(context.Context, *WritePayloadRequest) (*WritePayloadResponse, error)
+	// WritePayloads writes a list of payloads into a topic.
+	WritePayloads(context.Context, *WritePayloadsRequest) (*WritePayloadsResponse, error)
+	// ReadPayload reads the payload stored at a given offset.
+	ReadPayload(context.Context, *ReadPayloadRequest) (*Record, error)
+	// CreateScanner creates a new scanner on a topic.
+	CreateScanner(context.Context, *CreateScannerRequest) (*ScannerInfo, error)
+	// DeleteScanner deletes an existing scanner.
+	DeleteScanner(context.Context, *DeleteScannerRequest) (*DeleteScannerResponse, error)
+	// AckScanner acknowledges a message scanned by a scanner in ack mode.
+	AckScanner(context.Context, *AckScannerRequest) (*AckScannerResponse, error)
+	// Scan returns the next message of a scanner, waiting for it if requested.
+	Scan(context.Context, *ScanRequest) (*Record, error)
+	// Sync flushes all topic data to disk.
+	Sync(context.Context, *SyncRequest) (*SyncResponse, error)
+	// Check checks the integrity of a topic.
+	Check(context.Context, *CheckRequest) (*CheckResponse, error)
+	// Subscribe streams all messages of a topic as they get written.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error
+	mustEmbedUnimplementedNetLogServer()
+}
+
+// UnimplementedNetLogServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNetLogServer struct{}
+
+func (UnimplementedNetLogServer) ServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServerInfo not implemented")
+}
+func (UnimplementedNetLogServer) CreateTopic(context.Context, *CreateTopicRequest) (*CreateTopicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTopic not implemented")
+}
+func (UnimplementedNetLogServer) DeleteTopic(context.Context, *DeleteTopicRequest) (*DeleteTopicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTopic not implemented")
+}
+func (UnimplementedNetLogServer) TopicInfo(context.Context, *TopicInfoRequest) (*TopicInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopicInfo not implemented")
+}
+func (UnimplementedNetLogServer) WritePayload(context.Context, *WritePayloadRequest) (*WritePayloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WritePayload not implemented")
+}
+func (UnimplementedNetLogServer) WritePayloads(context.Context, *WritePayloadsRequest) (*WritePayloadsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WritePayloads not implemented")
+}
+func (UnimplementedNetLogServer) ReadPayload(context.Context, *ReadPayloadRequest) (*Record, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadPayload not implemented")
+}
+func (UnimplementedNetLogServer) CreateScanner(context.Context, *CreateScannerRequest) (*ScannerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateScanner not implemented")
+}
+func (UnimplementedNetLogServer) DeleteScanner(context.Context, *DeleteScannerRequest) (*DeleteScannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScanner not implemented")
+}
+func (UnimplementedNetLogServer) AckScanner(context.Context, *AckScannerRequest) (*AckScannerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckScanner not implemented")
+}
+func (UnimplementedNetLogServer) Scan(context.Context, *ScanRequest) (*Record, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedNetLogServer) Sync(context.Context, *SyncRequest) (*SyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedNetLogServer) Check(context.Context, *CheckRequest) (*CheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedNetLogServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[SubscribeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedNetLogServer) mustEmbedUnimplementedNetLogServer() {}
+func (UnimplementedNetLogServer) testEmbeddedByValue()                {}
+
+// UnsafeNetLogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NetLogServer will
+// result in compilation errors.
+type UnsafeNetLogServer interface {
+	mustEmbedUnimplementedNetLogServer()
+}
+
+func RegisterNetLogServer(s grpc.ServiceRegistrar, srv NetLogServer) {
+	// If the following call pancis, it indicates UnimplementedNetLogServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NetLog_ServiceDesc, srv)
+}
+
+func _NetLog_ServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).ServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_ServerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).ServerInfo(ctx, req.(*ServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_CreateTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).CreateTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_CreateTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).CreateTopic(ctx, req.(*CreateTopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_DeleteTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).DeleteTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_DeleteTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).DeleteTopic(ctx, req.(*DeleteTopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_TopicInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).TopicInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_TopicInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).TopicInfo(ctx, req.(*TopicInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_WritePayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WritePayloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).WritePayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_WritePayload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).WritePayload(ctx, req.(*WritePayloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_WritePayloads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WritePayloadsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).WritePayloads(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_WritePayloads_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).WritePayloads(ctx, req.(*WritePayloadsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_ReadPayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadPayloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).ReadPayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_ReadPayload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).ReadPayload(ctx, req.(*ReadPayloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_CreateScanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).CreateScanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_CreateScanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).CreateScanner(ctx, req.(*CreateScannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_DeleteScanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).DeleteScanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_DeleteScanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).DeleteScanner(ctx, req.(*DeleteScannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_AckScanner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckScannerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).AckScanner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_AckScanner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).AckScanner(ctx, req.(*AckScannerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_Scan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_Sync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).Sync(ctx, req.(*SyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetLogServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetLog_Check_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetLogServer).Check(ctx, req.(*CheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetLog_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetLogServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, SubscribeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NetLog_SubscribeServer = grpc.ServerStreamingServer[SubscribeResponse]
+
+// NetLog_ServiceDesc is the grpc.ServiceDesc for NetLog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NetLog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "netlog.NetLog",
+	HandlerType: (*NetLogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ServerInfo",
+			Handler:    _NetLog_ServerInfo_Handler,
+		},
+		{
+			MethodName: "CreateTopic",
+			Handler:    _NetLog_CreateTopic_Handler,
+		},
+		{
+			MethodName: "DeleteTopic",
+			Handler:    _NetLog_DeleteTopic_Handler,
+		},
+		{
+			MethodName: "TopicInfo",
+			Handler:    _NetLog_TopicInfo_Handler,
+		},
+		{
+			MethodName: "WritePayload",
+			Handler:    _NetLog_WritePayload_Handler,
+		},
+		{
+			MethodName: "WritePayloads",
+			Handler:    _NetLog_WritePayloads_Handler,
+		},
+		{
+			MethodName: "ReadPayload",
+			Handler:    _NetLog_ReadPayload_Handler,
+		},
+		{
+			MethodName: "CreateScanner",
+			Handler:    _NetLog_CreateScanner_Handler,
+		},
+		{
+			MethodName: "DeleteScanner",
+			Handler:    _NetLog_DeleteScanner_Handler,
+		},
+		{
+			MethodName: "AckScanner",
+			Handler:    _NetLog_AckScanner_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _NetLog_Scan_Handler,
+		},
+		{
+			MethodName: "Sync",
+			Handler:    _NetLog_Sync_Handler,
+		},
+		{
+			MethodName: "Check",
+			Handler:    _NetLog_Check_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _NetLog_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "netlog.proto",
+}
@@ -0,0 +1,578 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"hash/crc32"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog"
+	"github.com/ninibe/netlog/biglog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// default limits of each response sent by Subscribe
+	defaultStreamOffsets = 1000
+	defaultStreamBytes   = 1024 * 1024
+)
+
+// NewTransport sets up a gRPC interface around a NetLog.
+func NewTransport(nl *netlog.NetLog) *Transport {
+	return &Transport{nl: nl}
+}
+
+// Transport implements the NetLog gRPC service around a NetLog.
+type Transport struct {
+	UnimplementedNetLogServer
+	nl *netlog.NetLog
+}
+
+// Register registers the NetLog service in a gRPC server.
+func (gt *Transport) Register(s *grpc.Server) {
+	RegisterNetLogServer(s, gt)
+}
+
+// ServerInfo returns the list of existing topics.
+func (gt *Transport) ServerInfo(ctx context.Context, req *ServerInfoRequest) (*ServerInfoResponse, error) {
+	return &ServerInfoResponse{Topics: gt.nl.TopicList()}, nil
+}
+
+// CreateTopic creates a new topic.
+func (gt *Transport) CreateTopic(ctx context.Context, req *CreateTopicRequest) (*CreateTopicResponse, error) {
+	settings, err := topicSettings(req.GetSettings())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	_, err = gt.nl.CreateTopic(req.GetTopic(), settings)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &CreateTopicResponse{}, nil
+}
+
+// DeleteTopic deletes an existing topic.
+func (gt *Transport) DeleteTopic(ctx context.Context, req *DeleteTopicRequest) (*DeleteTopicResponse, error) {
+	err := gt.nl.DeleteTopic(req.GetTopic(), req.GetForce())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &DeleteTopicResponse{}, nil
+}
+
+// TopicInfo returns the information of a topic.
+func (gt *Transport) TopicInfo(ctx context.Context, req *TopicInfoRequest) (*TopicInfoResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	info, err := t.Info()
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return topicInfo(info), nil
+}
+
+// WritePayload writes a single payload into a topic.
+func (gt *Transport) WritePayload(ctx context.Context, req *WritePayloadRequest) (*WritePayloadResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	// only tombstones, keys without payload, can be empty
+	if len(req.GetPayload()) == 0 && len(req.GetKey()) == 0 {
+		return nil, errStatus(netlog.ErrBadRequest)
+	}
+
+	durability, err := netlog.ParseDurability(req.GetDurability())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	offset, err := t.WriteMessage(messageFromRequest(req))
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	err = t.WaitDurable(ctx, offset, durability)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &WritePayloadResponse{Offset: offset}, nil
+}
+
+// WritePayloads writes a list of payloads into a topic as a single batch.
+func (gt *Transport) WritePayloads(ctx context.Context, req *WritePayloadsRequest) (*WritePayloadsResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	durability, err := netlog.ParseDurability(req.GetDurability())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	msgs := make([]netlog.Message, len(req.GetPayloads()))
+	for k, p := range req.GetPayloads() {
+		if len(p) == 0 {
+			return nil, errStatus(netlog.ErrBadRequest)
+		}
+
+		msgs[k] = netlog.MessageFromPayload(p)
+	}
+
+	offset, err := t.WriteMessages(msgs)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	err = t.WaitDurable(ctx, offset+int64(len(msgs))-1, durability)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &WritePayloadsResponse{Offset: offset, Count: int64(len(msgs))}, nil
+}
+
+// ReadPayload reads the payload stored at a given offset.
+func (gt *Transport) ReadPayload(ctx context.Context, req *ReadPayloadRequest) (*Record, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	offset, err := t.ParseOffset(req.GetOffset())
+	if err != nil {
+		return nil, errStatus(netlog.ErrInvalidOffset)
+	}
+
+	m, err := t.Message(offset)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return newRecord(offset, m), nil
+}
+
+// messageFromRequest returns a message with metadata if the request carries any.
+func messageFromRequest(req *WritePayloadRequest) netlog.Message {
+	if req.Key == nil && req.Timestamp == nil && len(req.Headers) == 0 {
+		return netlog.MessageFromPayload(req.GetPayload())
+	}
+
+	meta := netlog.MessageMeta{
+		Key:       req.GetKey(),
+		Timestamp: time.Now(),
+		Headers:   req.GetHeaders(),
+	}
+
+	if req.Timestamp != nil {
+		meta.Timestamp = req.Timestamp.AsTime()
+	}
+
+	return netlog.MessageWithMeta(req.GetPayload(), meta)
+}
+
+// newRecord returns the record of message m stored at offset.
+func newRecord(offset int64, m netlog.Message) *Record {
+	rec := &Record{
+		Offset:  offset,
+		Payload: m.Payload(),
+		Crc32:   crc32.ChecksumIEEE(m.Payload()),
+	}
+
+	meta, err := m.Meta()
+	if err != nil {
+		return rec
+	}
+
+	rec.Key = meta.Key
+	rec.Headers = meta.Headers
+	if !meta.Timestamp.IsZero() {
+		rec.Timestamp = timestamppb.New(meta.Timestamp)
+	}
+
+	return rec
+}
+
+// CreateScanner creates a new scanner on a topic.
+func (gt *Transport) CreateScanner(ctx context.Context, req *CreateScannerRequest) (*ScannerInfo, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	from, err := t.ParseOffset(req.GetFrom())
+	if err != nil {
+		return nil, errStatus(netlog.ErrBadRequest)
+	}
+
+	var ts netlog.TopicScanner
+	if req.GetAck() {
+		visibility := netlog.DefaultVisibility
+		if req.GetVisibility() != "" {
+			bd, err2 := bigduration.ParseBigDuration(req.GetVisibility())
+			if err2 != nil {
+				return nil, errStatus(netlog.ErrInvalidDuration)
+			}
+
+			visibility = bd.Duration()
+		}
+
+		ts, err = t.NewAckScanner(from, visibility)
+	} else {
+		ts, err = t.NewScanner(from, req.GetPersist())
+	}
+
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return scannerInfo(ts.Info()), nil
+}
+
+// AckScanner acknowledges a message scanned by a scanner in ack mode.
+func (gt *Transport) AckScanner(ctx context.Context, req *AckScannerRequest) (*AckScannerResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	err = t.AckScanner(req.GetId(), req.GetOffset())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &AckScannerResponse{}, nil
+}
+
+// DeleteScanner deletes an existing scanner.
+func (gt *Transport) DeleteScanner(ctx context.Context, req *DeleteScannerRequest) (*DeleteScannerResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	if req.GetId() == "" {
+		return nil, errStatus(netlog.ErrBadRequest)
+	}
+
+	err = t.DeleteScanner(req.GetId())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &DeleteScannerResponse{}, nil
+}
+
+// Scan returns the next message of a scanner.
+func (gt *Transport) Scan(ctx context.Context, req *ScanRequest) (*Record, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	sc, err := t.Scanner(req.GetId())
+	if err != nil {
+		return nil, errStatus(netlog.ErrScannerNotFound)
+	}
+
+	timeout := 5 * time.Millisecond
+	if req.GetWait() != "" {
+		bd, err := bigduration.ParseBigDuration(req.GetWait())
+		if err != nil {
+			return nil, errStatus(netlog.ErrInvalidDuration)
+		}
+
+		timeout = bd.Duration()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	m, o, err := sc.Scan(ctx)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return newRecord(o, m), nil
+}
+
+// Sync flushes all topic data to disk.
+func (gt *Transport) Sync(ctx context.Context, req *SyncRequest) (*SyncResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	err = t.Sync()
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	return &SyncResponse{}, nil
+}
+
+// Check checks the integrity of a topic.
+func (gt *Transport) Check(ctx context.Context, req *CheckRequest) (*CheckResponse, error) {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return nil, errStatus(err)
+	}
+
+	from, err := t.ParseOffset(req.GetFrom())
+	if err != nil {
+		return nil, errStatus(netlog.ErrBadRequest)
+	}
+
+	iErrs, err := t.CheckIntegrity(ctx, from)
+	if err != nil {
+		return nil, errStatus(netlog.ErrBadRequest)
+	}
+
+	res := &CheckResponse{Errors: make([]*IntegrityError, len(iErrs))}
+	for k, ie := range iErrs {
+		res.Errors[k] = &IntegrityError{
+			Offset:   ie.Offset,
+			Odelta:   int32(ie.ODelta),
+			Type:     string(ie.Type),
+			Expected: ie.Expected,
+			Actual:   ie.Actual,
+		}
+	}
+
+	return res, nil
+}
+
+// Subscribe streams all messages of a topic starting at the given offset,
+// blocking for new messages until the client cancels the call.
+func (gt *Transport) Subscribe(req *SubscribeRequest, stream NetLog_SubscribeServer) error {
+	t, err := gt.nl.Topic(req.GetTopic())
+	if err != nil {
+		return errStatus(err)
+	}
+
+	from, err := t.ParseOffset(req.GetFrom())
+	if err != nil {
+		return errStatus(netlog.ErrInvalidOffset)
+	}
+
+	maxOffsets := req.GetMaxOffsets()
+	if maxOffsets <= 0 {
+		maxOffsets = defaultStreamOffsets
+	}
+
+	maxBytes := req.GetMaxBytes()
+	if maxBytes <= 0 {
+		maxBytes = defaultStreamBytes
+	}
+
+	st, err := t.NewStreamer(from)
+	if err != nil {
+		return errStatus(err)
+	}
+
+	defer logClose(st)
+
+	buf := &bytes.Buffer{}
+	for {
+		buf.Reset()
+		_, err = st.Next(stream.Context(), buf, maxOffsets, maxBytes)
+		if err != nil {
+			return errStatus(err)
+		}
+
+		h, err := netlog.ReadDeltaHeader(buf)
+		if err != nil {
+			return errStatus(err)
+		}
+
+		msgs, err := netlog.UnpackDelta(buf.Bytes())
+		if err != nil {
+			return errStatus(err)
+		}
+
+		res := &SubscribeResponse{Records: make([]*Record, 0, len(msgs))}
+		for k, m := range msgs {
+			// the streamer starts at the beginning of
+			// the message-set containing `from`
+			offset := h.Offset + int64(k)
+			if offset < from {
+				continue
+			}
+
+			res.Records = append(res.Records, newRecord(offset, m))
+		}
+
+		if len(res.Records) == 0 {
+			continue
+		}
+
+		err = stream.Send(res)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func topicSettings(s *TopicSettings) (settings netlog.TopicSettings, err error) {
+	if s.GetSegmentAge() != "" {
+		settings.SegAge, err = bigduration.ParseBigDuration(s.GetSegmentAge())
+		if err != nil {
+			return settings, netlog.ErrInvalidDuration
+		}
+	}
+
+	if s.GetBatchInterval() != "" {
+		settings.BatchInterval, err = bigduration.ParseBigDuration(s.GetBatchInterval())
+		if err != nil {
+			return settings, netlog.ErrInvalidDuration
+		}
+	}
+
+	if s.GetCompressionType() < 0 || s.GetCompressionType() > int32(netlog.CompressionSnappy) {
+		return settings, netlog.ErrInvalidCompression
+	}
+
+	settings.Durability, err = netlog.ParseDurability(s.GetDurability())
+	if err != nil {
+		return settings, err
+	}
+
+	settings.CleanupPolicy, err = netlog.ParseCleanupPolicy(s.GetCleanupPolicy())
+	if err != nil {
+		return settings, err
+	}
+
+	if s.GetTombstoneRetention() != "" {
+		settings.TombstoneRetention, err = bigduration.ParseBigDuration(s.GetTombstoneRetention())
+		if err != nil {
+			return settings, netlog.ErrInvalidDuration
+		}
+	}
+
+	settings.QuotaPolicy, err = netlog.ParseQuotaPolicy(s.GetQuotaPolicy())
+	if err != nil {
+		return settings, err
+	}
+
+	if s.GetMaxSegmentAge() != "" {
+		settings.MaxSegAge, err = bigduration.ParseBigDuration(s.GetMaxSegmentAge())
+		if err != nil {
+			return settings, netlog.ErrInvalidDuration
+		}
+	}
+
+	settings.SegSize = s.GetSegmentSize()
+	settings.MaxBytes = s.GetMaxTopicBytes()
+	settings.ScannerRetention = s.GetScannerRetention()
+	settings.BatchNumMessages = int(s.GetBatchNumMessages())
+	settings.CompressionType = netlog.CompressionType(s.GetCompressionType())
+	return settings, nil
+}
+
+func topicInfo(info *netlog.TopicInfo) *TopicInfoResponse {
+	res := &TopicInfoResponse{
+		Name:            info.Name,
+		Path:            info.Path,
+		DiskSize:        info.DiskSize,
+		FirstOffset:     info.FirstOffset,
+		LatestOffset:    info.LatestOffset,
+		Segments:        make([]*SegmentInfo, len(info.Segments)),
+		ModTime:         timestamppb.New(info.ModTime),
+		Scanners:        make(map[string]*ScannerInfo, len(info.Scanners)),
+		Streamers:       int32(info.Streamers),
+		LaggingScanners: info.Lagging,
+	}
+
+	for k, s := range info.Segments {
+		res.Segments[k] = segmentInfo(s)
+	}
+
+	for k, s := range info.Scanners {
+		res.Scanners[k] = scannerInfo(s)
+	}
+
+	return res
+}
+
+func segmentInfo(s *biglog.SegInfo) *SegmentInfo {
+	return &SegmentInfo{
+		FirstOffset: s.FirstOffset,
+		DiskSize:    s.DiskSize,
+		DataSize:    s.DataSize,
+		ModTime:     timestamppb.New(s.ModTime),
+	}
+}
+
+func scannerInfo(s netlog.TScannerInfo) *ScannerInfo {
+	return &ScannerInfo{
+		Id:         s.ID,
+		Next:       s.Next,
+		From:       s.From,
+		Persistent: s.Persist,
+		Ack:        s.Ack,
+		Pending:    int32(s.Pending),
+	}
+}
+
+// errStatus maps NetLog errors into gRPC status errors.
+func errStatus(err error) error {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+
+	e := netlog.ExtErr(err)
+	return status.Error(errCode(e), e.Error())
+}
+
+func errCode(e netlog.NLError) codes.Code {
+	switch e {
+	case netlog.ErrTopicExists:
+		return codes.AlreadyExists
+	case netlog.ErrEndOfTopic:
+		return codes.OutOfRange
+	}
+
+	switch e.StatusCode() {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusInsufficientStorage:
+		return codes.ResourceExhausted
+	}
+
+	return codes.Internal
+}
+
+// logClose calls Close on the subject and logs the error if any
+// this is handy to call Close on defer
+func logClose(c io.Closer) {
+	err := c.Close()
+	if err != nil {
+		log.Printf("error: %s", err)
+	}
+}
@@ -0,0 +1,205 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"comail.io/go/colog"
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	colog.Register()
+	colog.SetMinLevel(colog.LError)
+	grpclog.SetLoggerV2(grpclog.NewLoggerV2(ioutil.Discard, ioutil.Discard, ioutil.Discard))
+}
+
+func runTestGRPCServer() (NetLogClient, func()) {
+	rand.Seed(int64(time.Now().Nanosecond()))
+	datadir := filepath.Join(os.TempDir(), fmt.Sprintf("netlogtest-%d", rand.Int63()))
+	panicOn(os.Mkdir(datadir, 0755))
+
+	bd, err := bigduration.ParseBigDuration("1day")
+	panicOn(err)
+	nl, err := netlog.NewNetLog(datadir, netlog.MonitorInterval(bd))
+	panicOn(err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	panicOn(err)
+
+	server := grpc.NewServer()
+	NewTransport(nl).Register(server)
+	go func() { _ = server.Serve(lis) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	panicOn(err)
+
+	return NewNetLogClient(conn), func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+func TestTransport(t *testing.T) {
+	client, stop := runTestGRPCServer()
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := client.CreateTopic(ctx, &CreateTopicRequest{Topic: "grpc_test"})
+	panicOn(err)
+
+	_, err = client.CreateTopic(ctx, &CreateTopicRequest{Topic: "grpc_test"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", err)
+	}
+
+	_, err = client.TopicInfo(ctx, &TopicInfoRequest{Topic: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	data := randDataSet(20, 1024)
+	_, err = client.WritePayload(ctx, &WritePayloadRequest{Topic: "grpc_test", Payload: data[0]})
+	panicOn(err)
+	wres, err := client.WritePayloads(ctx, &WritePayloadsRequest{Topic: "grpc_test", Payloads: data[1:10]})
+	panicOn(err)
+	if wres.Offset != 1 || wres.Count != 9 {
+		t.Errorf("Unexpected offsets written %v", wres)
+	}
+	_, err = client.Sync(ctx, &SyncRequest{Topic: "grpc_test"})
+	panicOn(err)
+
+	rec, err := client.ReadPayload(ctx, &ReadPayloadRequest{Topic: "grpc_test", Offset: "3"})
+	panicOn(err)
+	if rec.Offset != 3 || !bytes.Equal(rec.Payload, data[3]) {
+		t.Errorf("Bad payload read at offset %d", rec.Offset)
+	}
+
+	sc, err := client.CreateScanner(ctx, &CreateScannerRequest{Topic: "grpc_test", From: "8"})
+	panicOn(err)
+
+	for k := 8; k < 10; k++ {
+		rec, err = client.Scan(ctx, &ScanRequest{Topic: "grpc_test", Id: sc.Id})
+		panicOn(err)
+		if rec.Offset != int64(k) || !bytes.Equal(rec.Payload, data[k]) {
+			t.Errorf("Bad payload scanned at offset %d", rec.Offset)
+		}
+	}
+
+	_, err = client.Scan(ctx, &ScanRequest{Topic: "grpc_test", Id: sc.Id})
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("Expected OutOfRange, got %v", err)
+	}
+
+	info, err := client.TopicInfo(ctx, &TopicInfoRequest{Topic: "grpc_test"})
+	panicOn(err)
+	if info.LatestOffset != 9 || info.Scanners[sc.Id] == nil {
+		t.Errorf("Bad topic info %v", info)
+	}
+
+	_, err = client.DeleteScanner(ctx, &DeleteScannerRequest{Topic: "grpc_test", Id: sc.Id})
+	panicOn(err)
+
+	check, err := client.Check(ctx, &CheckRequest{Topic: "grpc_test"})
+	panicOn(err)
+	if len(check.Errors) != 0 {
+		t.Errorf("Unexpected integrity errors %v", check.Errors)
+	}
+
+	// the checksum covers only the payload of messages with metadata
+	_, err = client.WritePayload(ctx, &WritePayloadRequest{Topic: "grpc_test", Payload: data[10], Key: []byte("k"), Durability: "flushed"})
+	panicOn(err)
+	rec, err = client.ReadPayload(ctx, &ReadPayloadRequest{Topic: "grpc_test", Offset: "10"})
+	panicOn(err)
+	if rec.Crc32 != crc32.ChecksumIEEE(data[10]) {
+		t.Errorf("Bad checksum of payload with metadata. Got: %d Exp: %d", rec.Crc32, crc32.ChecksumIEEE(data[10]))
+	}
+
+	_, err = client.DeleteTopic(ctx, &DeleteTopicRequest{Topic: "grpc_test", Force: true})
+	panicOn(err)
+}
+
+func TestSubscribe(t *testing.T) {
+	client, stop := runTestGRPCServer()
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := client.CreateTopic(ctx, &CreateTopicRequest{
+		Topic: "subscribe_test",
+		Settings: &TopicSettings{
+			BatchNumMessages: 4,
+			BatchInterval:    "1h",
+			CompressionType:  int32(netlog.CompressionGzip),
+		},
+	})
+	panicOn(err)
+
+	data := randDataSet(20, 1024)
+	_, err = client.WritePayloads(ctx, &WritePayloadsRequest{Topic: "subscribe_test", Payloads: data[:10]})
+	panicOn(err)
+	_, err = client.Sync(ctx, &SyncRequest{Topic: "subscribe_test"})
+	panicOn(err)
+
+	// start in the middle of a message-set
+	stream, err := client.Subscribe(ctx, &SubscribeRequest{Topic: "subscribe_test", From: "2", MaxOffsets: 10})
+	panicOn(err)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, err2 := client.WritePayloads(ctx, &WritePayloadsRequest{Topic: "subscribe_test", Payloads: data[10:]})
+		panicOn(err2)
+	}()
+
+	next := int64(2)
+	for next < 20 {
+		res, err := stream.Recv()
+		panicOn(err)
+
+		for _, rec := range res.Records {
+			if rec.Offset != next || !bytes.Equal(rec.Payload, data[next]) {
+				t.Fatalf("Bad record received at offset %d, expected %d", rec.Offset, next)
+			}
+			next++
+		}
+	}
+}
+
+func randDataSet(entries, size int) [][]byte {
+	set := make([][]byte, entries)
+	for k := range set {
+		set[k] = make([]byte, size)
+		_, _ = crand.Read(set[k])
+	}
+
+	return set
+}
+
+func panicOn(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
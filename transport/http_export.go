@@ -0,0 +1,68 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ninibe/netlog"
+)
+
+func (ht *HTTPTransport) handleExportTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	from, err := t.ParseOffset(r.URL.Query().Get("from"))
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrInvalidOffset)
+		return
+	}
+
+	// until the latest offset by default
+	to := int64(-1)
+	if str := r.URL.Query().Get("to"); str != "" {
+		to, err = t.ParseOffset(str)
+		if err != nil {
+			JSONErrorResponse(w, netlog.ErrInvalidOffset)
+			return
+		}
+	}
+
+	// errors once the export started are reported in the trailer
+	w.Header().Set("Trailer", "X-error")
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", t.Name()+".tar.gz"))
+	w.WriteHeader(http.StatusOK)
+
+	err = t.Export(w, from, to)
+	if err != nil && r.Context().Err() == nil {
+		log.Printf("warn: export of %q interrupted: %s", t.Name(), err)
+		w.Header().Set("X-error", netlog.ExtErr(err).Error())
+	}
+}
+
+func (ht *HTTPTransport) handleImportTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	preserve := trueStr(r.URL.Query().Get("preserve_offsets"))
+	t, err := ht.nl.ImportTopic(ps.ByName("topic"), r.Body, preserve)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	info, err := t.Info()
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	JSONResponse(w, info)
+}
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ninibe/netlog"
+)
+
+// default number of messages returned by a group fetch
+const defaultFetchMessages = 1
+
+func (ht *HTTPTransport) group(ps httprouter.Params) (*netlog.ConsumerGroup, error) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ConsumerGroup(ps.ByName("group"))
+}
+
+func (ht *HTTPTransport) handleGroupInfo(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	g, err := ht.group(ps)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONResponse(w, g.Info())
+}
+
+func (ht *HTTPTransport) handleDeleteGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	err = t.DeleteConsumerGroup(ps.ByName("group"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONOKResponse(w, "group deleted")
+}
+
+// handleJoinGroup adds a member to a group, creating the group
+// starting at the offset `from` if it does not exist yet.
+func (ht *HTTPTransport) handleJoinGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	g, err := t.ConsumerGroup(ps.ByName("group"))
+	if err == netlog.ErrGroupNotFound {
+		var from int64
+		from, err = t.ParseOffset(r.URL.Query().Get("from"))
+		if err != nil {
+			JSONErrorResponse(w, netlog.ErrInvalidOffset)
+			return
+		}
+
+		g, err = t.NewConsumerGroup(ps.ByName("group"), from)
+		// created concurrently
+		if err == netlog.ErrGroupExists {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	JSONResponse(w, IDMsg{ID: g.Join()})
+}
+
+func (ht *HTTPTransport) handleLeaveGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	g, err := ht.group(ps)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	err = g.Leave(r.URL.Query().Get("member"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONOKResponse(w, "left group")
+}
+
+// handleFetchGroup returns the messages of up to `max` offsets claimed for a member framed as stored.
+func (ht *HTTPTransport) handleFetchGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	g, err := ht.group(ps)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	max, err := intParam(r, "max", defaultFetchMessages)
+	if err != nil || max < 1 {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	timeout, err := waitParam(r)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	msgs, offsets, err := g.Fetch(ctx, r.URL.Query().Get("member"), int(max))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	writeMessages(w, msgs, offsets)
+}
+
+func (ht *HTTPTransport) handleCommitGroup(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	g, err := ht.group(ps)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrInvalidOffset)
+		return
+	}
+
+	err = g.Commit(r.URL.Query().Get("member"), offset)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONOKResponse(w, "committed")
+}
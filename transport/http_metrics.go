@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ninibe/netlog/metrics"
+)
+
+// timedRouter is an httprouter.Router recording the latency of the requests of each route.
+type timedRouter struct {
+	*httprouter.Router
+	latency *metrics.HistogramVec
+}
+
+// Handle registers a handler timing its requests under the route path.
+func (tr *timedRouter) Handle(method, path string, h httprouter.Handle) {
+	tr.Router.Handle(method, path, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		start := time.Now()
+		h(w, r, ps)
+		tr.latency.With("method", method, "route", path).Observe(time.Since(start).Seconds())
+	})
+}
+
+// GET is a shortcut for tr.Handle("GET", path, h)
+func (tr *timedRouter) GET(path string, h httprouter.Handle) { tr.Handle("GET", path, h) }
+
+// POST is a shortcut for tr.Handle("POST", path, h)
+func (tr *timedRouter) POST(path string, h httprouter.Handle) { tr.Handle("POST", path, h) }
+
+// PUT is a shortcut for tr.Handle("PUT", path, h)
+func (tr *timedRouter) PUT(path string, h httprouter.Handle) { tr.Handle("PUT", path, h) }
+
+// DELETE is a shortcut for tr.Handle("DELETE", path, h)
+func (tr *timedRouter) DELETE(path string, h httprouter.Handle) { tr.Handle("DELETE", path, h) }
+
+// MetricsHandler returns the handler of the metrics of the NetLog and of
+// the requests served by the transport in the Prometheus text format.
+func (ht *HTTPTransport) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mw := metrics.NewWriter(w)
+		ht.nl.CollectMetrics(mw)
+		ht.latency.Write(mw, "netlog_http_request_duration_seconds", "Latency of the HTTP requests per route.")
+
+		w.Header().Set("Content-Type", metrics.ContentType)
+		if err := mw.Flush(); err != nil {
+			log.Printf("warn: failed to write metrics: %s", err)
+		}
+	})
+}
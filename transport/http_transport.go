@@ -5,8 +5,11 @@
 package transport
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
 	"log"
@@ -18,32 +21,77 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/ninibe/bigduration"
 	"github.com/ninibe/netlog"
+	"github.com/ninibe/netlog/metrics"
+)
+
+const (
+	// default limits of each chunk of data sent by the streaming endpoint
+	defaultStreamOffsets = 1000
+	defaultStreamBytes   = 1024 * 1024
+)
+
+// request and response headers mapped to the metadata of messages,
+// header names after the prefix are in canonical MIME form.
+const (
+	keyHeader       = "X-Netlog-Key"
+	timestampHeader = "X-Netlog-Timestamp"
+	headerPrefix    = "X-Netlog-Header-"
 )
 
 // NewHTTPTransport transport sets up an HTTP interface around a NetLog.
-func NewHTTPTransport(nl *netlog.NetLog) *HTTPTransport {
-	return &HTTPTransport{nl: nl}
+func NewHTTPTransport(nl *netlog.NetLog, opts ...HTTPOption) *HTTPTransport {
+	ht := &HTTPTransport{
+		nl:      nl,
+		latency: metrics.NewHistogramVec(metrics.LatencyBuckets),
+	}
+
+	for _, opt := range opts {
+		opt(ht)
+	}
+
+	return ht
 }
 
 // HTTPTransport implements an HTTP server around a NetLog.
 type HTTPTransport struct {
-	nl *netlog.NetLog
+	nl      *netlog.NetLog
+	latency *metrics.HistogramVec
+	auth    Authenticator
+	acl     ACL
 }
 
 // ServeHTTP implements the http.Handler interface around a NetLog.
 func (ht *HTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	router := httprouter.New()
+	if r = ht.authenticate(w, r); r == nil {
+		return
+	}
+
+	router := &timedRouter{Router: httprouter.New(), latency: ht.latency}
 	router.GET("/", ht.handleServerInfo)
-	router.GET("/:topic", ht.handleTopicInfo)
-	router.POST("/:topic", ht.handleCreateTopic)
-	router.POST("/:topic/payload", ht.handleWritePayload)
-	router.GET("/:topic/payload/:offset", ht.handleReadPayload)
-	router.GET("/:topic/sync", ht.handleSync)
-	router.POST("/:topic/scanner", ht.handleCreateScanner)
-	router.DELETE("/:topic/scanner", ht.handleDeleteScanner)
-	router.GET("/:topic/scan", ht.handleScanTopic)
-	router.GET("/:topic/check", ht.handleCheckTopic)
-	router.DELETE("/:topic", ht.handleDeleteTopic)
+	router.GET("/:topic", ht.allow(RightConsume, ht.handleTopicInfo))
+	router.POST("/:topic", ht.allow(RightAdmin, ht.handleCreateTopic))
+	router.PUT("/:topic/settings", ht.allow(RightAdmin, ht.handleUpdateSettings))
+	router.POST("/:topic/payload", ht.allow(RightProduce, ht.handleWritePayload))
+	router.POST("/:topic/payloads", ht.allow(RightProduce, ht.handleWritePayloads))
+	router.GET("/:topic/payload/:offset", ht.allow(RightConsume, ht.handleReadPayload))
+	router.GET("/:topic/sync", ht.allow(RightProduce, ht.handleSync))
+	router.GET("/:topic/scanners", ht.allow(RightConsume, ht.handleListScanners))
+	router.POST("/:topic/scanner", ht.allow(RightConsume, ht.handleCreateScanner))
+	router.DELETE("/:topic/scanner", ht.allow(RightConsume, ht.handleDeleteScanner))
+	router.POST("/:topic/scanner/ack", ht.allow(RightConsume, ht.handleAckScanner))
+	router.GET("/:topic/scan", ht.allow(RightConsume, ht.handleScanTopic))
+	router.GET("/:topic/stream", ht.allow(RightConsume, ht.handleStreamTopic))
+	router.GET("/:topic/check", ht.allow(RightConsume, ht.handleCheckTopic))
+	router.POST("/:topic/repair", ht.allow(RightAdmin, ht.handleRepairTopic))
+	router.GET("/:topic/export", ht.allow(RightConsume, ht.handleExportTopic))
+	router.POST("/:topic/import", ht.allow(RightAdmin, ht.handleImportTopic))
+	router.GET("/:topic/groups/:group", ht.allow(RightConsume, ht.handleGroupInfo))
+	router.DELETE("/:topic/groups/:group", ht.allow(RightAdmin, ht.handleDeleteGroup))
+	router.POST("/:topic/groups/:group/join", ht.allow(RightConsume, ht.handleJoinGroup))
+	router.POST("/:topic/groups/:group/leave", ht.allow(RightConsume, ht.handleLeaveGroup))
+	router.GET("/:topic/groups/:group/fetch", ht.allow(RightConsume, ht.handleFetchGroup))
+	router.POST("/:topic/groups/:group/commit", ht.allow(RightConsume, ht.handleCommitGroup))
+	router.DELETE("/:topic", ht.allow(RightAdmin, ht.handleDeleteTopic))
 	router.ServeHTTP(w, r)
 }
 
@@ -68,6 +116,30 @@ func (ht *HTTPTransport) handleCreateTopic(w http.ResponseWriter, r *http.Reques
 	JSONOKResponse(w, "topic created")
 }
 
+func (ht *HTTPTransport) handleUpdateSettings(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	var settings netlog.TopicSettings
+	err = json.NewDecoder(r.Body).Decode(&settings)
+	if err != nil && err != io.EOF {
+		log.Print(err)
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	err = t.UpdateSettings(settings)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONResponse(w, t.Settings())
+}
+
 func (ht *HTTPTransport) handleReadPayload(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	t, err := ht.nl.Topic(ps.ByName("topic"))
 	if err != nil {
@@ -83,14 +155,15 @@ func (ht *HTTPTransport) handleReadPayload(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	data, err := t.Payload(offset)
+	m, err := t.Message(offset)
 	if err != nil {
 		JSONErrorResponse(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/octet-stream")
-	_, err = w.Write(data)
+	setMetaHeaders(w, m)
+	_, err = w.Write(m.Payload())
 	if err != nil {
 		log.Printf("error: failed to write HTTP response %s", err)
 	}
@@ -105,26 +178,131 @@ func (ht *HTTPTransport) handleWritePayload(w http.ResponseWriter, r *http.Reque
 
 	defer logClose(r.Body)
 
+	durability, err := netlog.ParseDurability(r.URL.Query().Get("durability"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
 	buf, err := ioutil.ReadAll(r.Body)
-	if len(buf) == 0 || len(buf) < int(r.ContentLength) {
+	if len(buf) < int(r.ContentLength) {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	entry, err := messageFromRequest(r, buf)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	// only tombstones, keys without payload, can be empty
+	if len(buf) == 0 && entry.Key() == nil {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	offset, err := t.WriteMessage(entry)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	err = t.WaitDurable(r.Context(), offset, durability)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("X-offset", strconv.FormatInt(offset, 10))
+	w.WriteHeader(http.StatusCreated)
+	JSONResponse(w, OffsetsMsg{
+		Offset: offset,
+		Last:   offset,
+		Count:  1,
+	})
+}
+
+func (ht *HTTPTransport) handleWritePayloads(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	defer logClose(r.Body)
+
+	durability, err := netlog.ParseDurability(r.URL.Query().Get("durability"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	var msgs []netlog.Message
+	switch r.URL.Query().Get("format") {
+	case "", "messages":
+		msgs, err = netlog.ReadMessages(r.Body)
+	case "lines":
+		msgs, err = readLines(r.Body)
+	default:
+		err = netlog.ErrBadRequest
+	}
+
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	if len(msgs) == 0 {
 		JSONErrorResponse(w, netlog.ErrBadRequest)
 		return
 	}
 
+	offset, err := t.WriteMessages(msgs)
 	if err != nil {
 		JSONErrorResponse(w, err)
 		return
 	}
 
-	entry := netlog.MessageFromPayload(buf)
-	buf = entry.Bytes()
-	_, err = t.Write(buf)
+	last := offset + int64(len(msgs)) - 1
+	err = t.WaitDurable(r.Context(), last, durability)
 	if err != nil {
 		JSONErrorResponse(w, err)
 		return
 	}
 
+	w.Header().Set("X-offset", strconv.FormatInt(offset, 10))
 	w.WriteHeader(http.StatusCreated)
+	JSONResponse(w, OffsetsMsg{
+		Offset: offset,
+		Last:   last,
+		Count:  len(msgs),
+	})
+}
+
+// readLines reads newline-delimited payloads into messages skipping empty lines.
+func readLines(r io.Reader) (msgs []netlog.Message, err error) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		payload := bytes.TrimSuffix(line, []byte("\n"))
+		if len(payload) > 0 {
+			msgs = append(msgs, netlog.MessageFromPayload(payload))
+		}
+
+		if err == io.EOF {
+			return msgs, nil
+		}
+	}
 }
 
 func (ht *HTTPTransport) handleSync(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -159,8 +337,18 @@ func (ht *HTTPTransport) handleTopicInfo(w http.ResponseWriter, r *http.Request,
 	JSONResponse(w, info)
 }
 
+func (ht *HTTPTransport) handleListScanners(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONResponse(w, t.ScannersInfo())
+}
+
 func (ht *HTTPTransport) handleServerInfo(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	JSONResponse(w, ht.nl.TopicList())
+	JSONResponse(w, ht.visibleTopics(r, ht.nl.TopicList()))
 }
 
 func (ht *HTTPTransport) handleDeleteTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -174,6 +362,63 @@ func (ht *HTTPTransport) handleDeleteTopic(w http.ResponseWriter, r *http.Reques
 	JSONOKResponse(w, "topic deleted")
 }
 
+// messageFromRequest returns a message with the payload p, carrying the key and headers
+// of the request if any, in which case the timestamp defaults to the current time.
+func messageFromRequest(r *http.Request, p []byte) (netlog.Message, error) {
+	var meta netlog.MessageMeta
+	var hasMeta bool
+	for name, values := range r.Header {
+		if strings.HasPrefix(name, headerPrefix) && len(name) > len(headerPrefix) {
+			if meta.Headers == nil {
+				meta.Headers = make(map[string]string)
+			}
+			meta.Headers[name[len(headerPrefix):]] = values[0]
+			hasMeta = true
+		}
+	}
+
+	if _, ok := r.Header[keyHeader]; ok {
+		meta.Key = []byte(r.Header.Get(keyHeader))
+		hasMeta = true
+	}
+
+	meta.Timestamp = time.Now()
+	if ts := r.Header.Get(timestampHeader); ts != "" {
+		var err error
+		meta.Timestamp, err = time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, netlog.ErrBadRequest
+		}
+		hasMeta = true
+	}
+
+	if !hasMeta {
+		return netlog.MessageFromPayload(p), nil
+	}
+
+	return netlog.MessageWithMeta(p, meta), nil
+}
+
+// setMetaHeaders sets the key, timestamp and headers of m as response headers.
+func setMetaHeaders(w http.ResponseWriter, m netlog.Message) {
+	meta, err := m.Meta()
+	if err != nil {
+		return
+	}
+
+	if meta.Key != nil {
+		w.Header().Set(keyHeader, string(meta.Key))
+	}
+
+	if !meta.Timestamp.IsZero() {
+		w.Header().Set(timestampHeader, meta.Timestamp.UTC().Format(time.RFC3339Nano))
+	}
+
+	for name, value := range meta.Headers {
+		w.Header().Set(headerPrefix+name, value)
+	}
+}
+
 func (ht *HTTPTransport) handleScanTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	t, err := ht.nl.Topic(ps.ByName("topic"))
 	if err != nil {
@@ -187,24 +432,21 @@ func (ht *HTTPTransport) handleScanTopic(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	var timeout time.Duration
-	var bd bigduration.BigDuration
-
-	wait := r.URL.Query().Get("wait")
-	if wait == "" {
-		timeout = 5 * time.Millisecond
-	} else {
-		bd, err = bigduration.ParseBigDuration(wait)
-		if err != nil {
-			JSONErrorResponse(w, netlog.ErrInvalidDuration)
-			return
-		}
-
-		timeout = bd.Duration()
+	timeout, err := waitParam(r)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), timeout)
 	defer cancel()
+
+	q := r.URL.Query()
+	if q.Get("max_messages") != "" || q.Get("max_bytes") != "" {
+		ht.scanBatch(ctx, w, r, sc)
+		return
+	}
+
 	m, o, err := sc.Scan(ctx)
 	if err != nil {
 		JSONErrorResponse(w, err)
@@ -213,7 +455,9 @@ func (ht *HTTPTransport) handleScanTopic(w http.ResponseWriter, r *http.Request,
 
 	w.Header().Set("Content-Type", "application/octet-stream")
 	w.Header().Add("X-offset", strconv.FormatInt(o, 10))
-	w.Header().Add("X-crc32", strconv.FormatInt(int64(m.CRC32()), 10))
+	// the CRC32 covers only the payload, the metadata goes in its own headers
+	w.Header().Add("X-crc32", strconv.FormatInt(int64(crc32.ChecksumIEEE(m.Payload())), 10))
+	setMetaHeaders(w, m)
 
 	_, err = w.Write(m.Payload())
 	if err != nil {
@@ -221,6 +465,118 @@ func (ht *HTTPTransport) handleScanTopic(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// scanBatch responds with up to `max_messages` messages or `max_bytes` bytes
+// framed as stored, waiting only until the first message is available.
+// The delta headers written before every message don't count for `max_bytes`.
+func (ht *HTTPTransport) scanBatch(ctx context.Context, w http.ResponseWriter, r *http.Request, sc netlog.TopicScanner) {
+	maxMessages, err := intParam(r, "max_messages", defaultStreamOffsets)
+	if err != nil || maxMessages < 1 {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	maxBytes, err := intParam(r, "max_bytes", defaultStreamBytes)
+	if err != nil || maxBytes < 0 {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	msgs, offsets, err := sc.ScanN(ctx, int(maxMessages), maxBytes)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	writeMessages(w, msgs, offsets)
+}
+
+// writeMessages writes every framed message preceded by a DeltaHeader of its offset,
+// since offsets removed by compaction are skipped. The number of messages goes in the X-count header.
+func writeMessages(w http.ResponseWriter, msgs []netlog.Message, offsets []int64) {
+	if len(msgs) == 0 || len(msgs) != len(offsets) {
+		log.Printf("error: invalid batch of %d messages and %d offsets", len(msgs), len(offsets))
+		JSONErrorResponse(w, netlog.ErrEndOfTopic)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("X-count", strconv.Itoa(len(msgs)))
+
+	for k, m := range msgs {
+		h := netlog.DeltaHeader{Offset: offsets[k], ODelta: 1, Size: int64(len(m))}
+		_, err := w.Write(append(h.Bytes(), m...))
+		if err != nil {
+			log.Printf("error: failed to write HTTP response %s", err)
+			return
+		}
+	}
+}
+
+func (ht *HTTPTransport) handleStreamTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	from, err := t.ParseOffset(r.URL.Query().Get("from"))
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrInvalidOffset)
+		return
+	}
+
+	maxOffsets, err := intParam(r, "max_offsets", defaultStreamOffsets)
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	maxBytes, err := intParam(r, "max_bytes", defaultStreamBytes)
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrBadRequest)
+		return
+	}
+
+	st, err := t.NewStreamer(from)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	defer logClose(st)
+
+	// one stored entry per chunk keeps their boundaries
+	next := st.Next
+	if trueStr(r.URL.Query().Get("entries")) {
+		next = st.NextEntry
+	}
+
+	// errors once the stream started are reported in the trailer
+	w.Header().Set("Trailer", "X-error")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		_, err = next(r.Context(), w, maxOffsets, maxBytes)
+		if err != nil {
+			break
+		}
+	}
+
+	// client went away
+	if r.Context().Err() != nil {
+		return
+	}
+
+	log.Printf("warn: stream on %q interrupted: %s", t.Name(), err)
+	w.Header().Set("X-error", netlog.ExtErr(err).Error())
+}
+
 func (ht *HTTPTransport) handleCreateScanner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	t, err := ht.nl.Topic(ps.ByName("topic"))
 	if err != nil {
@@ -234,8 +590,25 @@ func (ht *HTTPTransport) handleCreateScanner(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	persist := trueStr(r.URL.Query().Get("persist"))
-	ts, err := t.NewScanner(from, persist)
+	var ts netlog.TopicScanner
+	if trueStr(r.URL.Query().Get("ack")) {
+		visibility := netlog.DefaultVisibility
+		if v := r.URL.Query().Get("visibility"); v != "" {
+			bd, err2 := bigduration.ParseBigDuration(v)
+			if err2 != nil {
+				JSONErrorResponse(w, netlog.ErrInvalidDuration)
+				return
+			}
+
+			visibility = bd.Duration()
+		}
+
+		ts, err = t.NewAckScanner(from, visibility)
+	} else {
+		persist := trueStr(r.URL.Query().Get("persist"))
+		ts, err = t.NewScanner(from, persist)
+	}
+
 	if err != nil {
 		JSONErrorResponse(w, err)
 		return
@@ -245,6 +618,28 @@ func (ht *HTTPTransport) handleCreateScanner(w http.ResponseWriter, r *http.Requ
 	JSONResponse(w, ts.Info())
 }
 
+func (ht *HTTPTransport) handleAckScanner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		JSONErrorResponse(w, netlog.ErrInvalidOffset)
+		return
+	}
+
+	err = t.AckScanner(r.URL.Query().Get("id"), offset)
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONOKResponse(w, "acked")
+}
+
 func (ht *HTTPTransport) handleDeleteScanner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	t, err := ht.nl.Topic(ps.ByName("topic"))
 	if err != nil {
@@ -280,6 +675,11 @@ func (ht *HTTPTransport) handleCheckTopic(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if trueStr(r.URL.Query().Get("progress")) {
+		ht.streamCheckTopic(w, r, t, from)
+		return
+	}
+
 	iErrs, err := t.CheckIntegrity(r.Context(), from)
 	if err != nil {
 		JSONErrorResponse(w, netlog.ErrBadRequest)
@@ -294,6 +694,75 @@ func (ht *HTTPTransport) handleCheckTopic(w http.ResponseWriter, r *http.Request
 	JSONResponse(w, iErrs)
 }
 
+// checkProgressInterval is how often the progress of integrity checks is streamed.
+const checkProgressInterval = time.Second
+
+// CheckProgressMsg is each line of a streamed integrity check, the last
+// one holding the final progress along with the errors found.
+type CheckProgressMsg struct {
+	Progress netlog.IntegrityProgress `json:"progress"`
+	Errors   []*netlog.IntegrityError `json:"errors,omitempty"`
+}
+
+// streamCheckTopic checks the integrity of a topic streaming its progress as one JSON object per line.
+func (ht *HTTPTransport) streamCheckTopic(w http.ResponseWriter, r *http.Request, t *netlog.Topic, from int64) {
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	msg := &CheckProgressMsg{}
+	var started bool
+	iErrs, err := t.CheckIntegrityProgress(r.Context(), from, checkProgressInterval, func(p netlog.IntegrityProgress) {
+		if msg.Progress = p; p.Done {
+			return
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			started = true
+		}
+
+		if enc.Encode(msg) == nil && flusher != nil {
+			flusher.Flush()
+		}
+	})
+
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	msg.Errors = iErrs
+	if err = enc.Encode(msg); err != nil {
+		log.Printf("warn: integrity check of %q interrupted: %s", t.Name(), err)
+	}
+}
+
+func (ht *HTTPTransport) handleRepairTopic(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	t, err := ht.nl.Topic(ps.ByName("topic"))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	reports, err := t.Repair(trueStr(r.URL.Query().Get("quarantine")))
+	if err != nil {
+		JSONErrorResponse(w, err)
+		return
+	}
+
+	JSONResponse(w, reports)
+}
+
+// OffsetsMsg is the response to writes with the offsets assigned to the written messages.
+type OffsetsMsg struct {
+	// Offset of the first message written.
+	Offset int64 `json:"offset"`
+	// Last offset written.
+	Last int64 `json:"last"`
+	// Count is the number of messages written.
+	Count int `json:"count"`
+}
+
 // IDMsg is the standard response when returning an ID
 type IDMsg struct {
 	ID string `json:"id"`
@@ -352,6 +821,31 @@ func JSONOKResponse(w http.ResponseWriter, message string) {
 	}
 }
 
+// intParam parses the query parameter `name` as an integer, returning def if not present.
+func intParam(r *http.Request, name string, def int64) (int64, error) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		return def, nil
+	}
+
+	return strconv.ParseInt(str, 10, 64)
+}
+
+// waitParam parses the `wait` query parameter, the time to wait for new messages.
+func waitParam(r *http.Request) (time.Duration, error) {
+	wait := r.URL.Query().Get("wait")
+	if wait == "" {
+		return 5 * time.Millisecond, nil
+	}
+
+	bd, err := bigduration.ParseBigDuration(wait)
+	if err != nil {
+		return 0, netlog.ErrInvalidDuration
+	}
+
+	return bd.Duration(), nil
+}
+
 func trueStr(s string) bool {
 	s = strings.ToLower(s)
 	return s == "1" || s == "true" || s == "yes"
@@ -0,0 +1,171 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ninibe/bigduration"
+	"github.com/ninibe/netlog"
+)
+
+func runTestKinesisServer() *httptest.Server {
+	rand.Seed(int64(time.Now().Nanosecond()))
+	datadir := filepath.Join(os.TempDir(), fmt.Sprintf("netlogtest-%d", rand.Int63()))
+	panicOn(os.Mkdir(datadir, 0755))
+
+	bd, err := bigduration.ParseBigDuration("1day")
+	panicOn(err)
+	nl, err := netlog.NewNetLog(datadir, netlog.MonitorInterval(bd))
+	panicOn(err)
+
+	return httptest.NewServer(NewKinesisTransport(nl))
+}
+
+func kinesisCall(t *testing.T, url, action string, req, res interface{}) (status int, errType string) {
+	body, err := json.Marshal(req)
+	panicOn(err)
+
+	hreq, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	panicOn(err)
+	hreq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	hreq.Header.Set("X-Amz-Target", "Kinesis_20131202."+action)
+
+	r, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logClose(r.Body)
+
+	if r.StatusCode != http.StatusOK {
+		var kerr kinesisError
+		panicOn(json.NewDecoder(r.Body).Decode(&kerr))
+		return r.StatusCode, kerr.Type
+	}
+
+	if res != nil {
+		panicOn(json.NewDecoder(r.Body).Decode(res))
+	}
+
+	return r.StatusCode, ""
+}
+
+func TestKinesis(t *testing.T) {
+	ts := runTestKinesisServer()
+	defer ts.Close()
+
+	stream := map[string]interface{}{"StreamName": "kinesis_test"}
+	if status, typ := kinesisCall(t, ts.URL, "CreateStream", map[string]interface{}{"StreamName": "kinesis_test", "ShardCount": 1}, nil); status != 200 {
+		t.Fatalf("CreateStream failed with %d %s", status, typ)
+	}
+
+	if _, typ := kinesisCall(t, ts.URL, "CreateStream", stream, nil); typ != kinesisErrInUse {
+		t.Errorf("Expected %s, got %q", kinesisErrInUse, typ)
+	}
+
+	if _, typ := kinesisCall(t, ts.URL, "DescribeStream", map[string]string{"StreamName": "missing"}, nil); typ != kinesisErrNotFound {
+		t.Errorf("Expected %s, got %q", kinesisErrNotFound, typ)
+	}
+
+	var list struct {
+		StreamNames    []string
+		HasMoreStreams bool
+	}
+	kinesisCall(t, ts.URL, "ListStreams", struct{}{}, &list)
+	if len(list.StreamNames) != 1 || list.StreamNames[0] != "kinesis_test" {
+		t.Errorf("Unexpected stream list %v", list.StreamNames)
+	}
+
+	var desc struct {
+		StreamDescription kinesisStreamDescription
+	}
+	kinesisCall(t, ts.URL, "DescribeStream", stream, &desc)
+	if len(desc.StreamDescription.Shards) != 1 || desc.StreamDescription.Shards[0].ShardID != kinesisShardID {
+		t.Errorf("Unexpected stream description %+v", desc)
+	}
+
+	data := randDataSet(10, 512)
+	var putOne kinesisPutResult
+	kinesisCall(t, ts.URL, "PutRecord", map[string]interface{}{
+		"StreamName": "kinesis_test", "Data": data[0], "PartitionKey": "k",
+	}, &putOne)
+	if putOne.SequenceNumber != "0" {
+		t.Errorf("Unexpected sequence number %q", putOne.SequenceNumber)
+	}
+
+	records := make([]map[string]interface{}, 0)
+	for _, d := range data[1:] {
+		records = append(records, map[string]interface{}{"Data": d, "PartitionKey": "k"})
+	}
+
+	var put struct {
+		FailedRecordCount int
+		Records           []kinesisPutResult
+	}
+	kinesisCall(t, ts.URL, "PutRecords", map[string]interface{}{"StreamName": "kinesis_test", "Records": records}, &put)
+	if put.FailedRecordCount != 0 {
+		t.Errorf("Failed to put %d records", put.FailedRecordCount)
+	}
+
+	for k, res := range put.Records {
+		if res.SequenceNumber != fmt.Sprint(k+1) {
+			t.Errorf("Unexpected sequence number %q for record %d", res.SequenceNumber, k+1)
+		}
+	}
+
+	var it kinesisIteratorRes
+	kinesisCall(t, ts.URL, "GetShardIterator", map[string]interface{}{
+		"StreamName":             "kinesis_test",
+		"ShardId":                kinesisShardID,
+		"ShardIteratorType":      "AFTER_SEQUENCE_NUMBER",
+		"StartingSequenceNumber": "1",
+	}, &it)
+
+	type getRecordsRes struct {
+		Records           []kinesisRecord
+		NextShardIterator string
+	}
+
+	var got getRecordsRes
+	kinesisCall(t, ts.URL, "GetRecords", map[string]interface{}{"ShardIterator": it.ShardIterator, "Limit": 5}, &got)
+	if len(got.Records) != 5 {
+		t.Fatalf("Expected 5 records, got %d", len(got.Records))
+	}
+
+	for k, rec := range got.Records {
+		if rec.SequenceNumber != fmt.Sprint(k+2) || !bytes.Equal(rec.Data, data[k+2]) || rec.PartitionKey != "k" {
+			t.Errorf("Bad record %s", rec.SequenceNumber)
+		}
+	}
+
+	kinesisCall(t, ts.URL, "GetRecords", map[string]interface{}{"ShardIterator": got.NextShardIterator}, &got)
+	if len(got.Records) != 3 || got.Records[2].SequenceNumber != "9" {
+		t.Fatalf("Unexpected records %v", got.Records)
+	}
+
+	// caught up, same iterator and no records
+	next := got.NextShardIterator
+	kinesisCall(t, ts.URL, "GetRecords", map[string]interface{}{"ShardIterator": next}, &got)
+	if len(got.Records) != 0 || got.NextShardIterator != next {
+		t.Errorf("Expected no records, got %d", len(got.Records))
+	}
+
+	if _, typ := kinesisCall(t, ts.URL, "GetRecords", map[string]string{"ShardIterator": "bogus"}, nil); typ != kinesisErrInvalidArgument {
+		t.Errorf("Expected %s, got %q", kinesisErrInvalidArgument, typ)
+	}
+
+	if status, typ := kinesisCall(t, ts.URL, "DeleteStream", stream, nil); status != 200 {
+		t.Errorf("DeleteStream failed with %d %s", status, typ)
+	}
+}
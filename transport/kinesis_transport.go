@@ -0,0 +1,578 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/comail/go-uuid/uuid"
+	"github.com/ninibe/netlog"
+)
+
+const (
+	kinesisTargetPrefix = "Kinesis_20131202."
+	kinesisShardID      = "shardId-000000000000"
+	kinesisMaxHashKey   = "340282366920938463463374607431768211455"
+	kinesisMaxRecords   = 10000
+	kinesisMaxPut       = 500
+)
+
+var kinesisStreamName = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,128}$`)
+
+// Kinesis error types as returned in the "__type" field of the response.
+const (
+	kinesisErrNotFound        = "ResourceNotFoundException"
+	kinesisErrInUse           = "ResourceInUseException"
+	kinesisErrInvalidArgument = "InvalidArgumentException"
+	kinesisErrSerialization   = "SerializationException"
+	kinesisErrUnknownAction   = "UnknownOperationException"
+	kinesisErrInternal        = "InternalFailure"
+)
+
+// NewKinesisTransport sets up an HTTP interface around a NetLog compatible with
+// the core actions of the Kinesis Data Streams JSON API. Every topic is exposed
+// as a stream with a single shard and the sequence numbers of the records are
+// their offsets in the topic. Only the JSON protocol is supported, AWS SDKs
+// using CBOR by default need to have it disabled.
+func NewKinesisTransport(nl *netlog.NetLog) *KinesisTransport {
+	return &KinesisTransport{nl: nl}
+}
+
+// KinesisTransport implements a Kinesis compatible HTTP server around a NetLog.
+type KinesisTransport struct {
+	nl *netlog.NetLog
+}
+
+// kinesisError is the body of any Kinesis error response.
+type kinesisError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+	status  int
+}
+
+func (e *kinesisError) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+func newKinesisErr(typ, message string) *kinesisError {
+	status := http.StatusBadRequest
+	if typ == kinesisErrInternal {
+		status = http.StatusInternalServerError
+	}
+
+	return &kinesisError{Type: typ, Message: message, status: status}
+}
+
+// ServeHTTP implements the http.Handler interface dispatching
+// requests to the action in the X-Amz-Target header.
+func (kt *KinesisTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer logClose(r.Body)
+
+	if r.Method != "POST" {
+		kinesisErrorResponse(w, newKinesisErr(kinesisErrUnknownAction, "only POST requests are supported"))
+		return
+	}
+
+	target := r.Header.Get("X-Amz-Target")
+	if !strings.HasPrefix(target, kinesisTargetPrefix) {
+		kinesisErrorResponse(w, newKinesisErr(kinesisErrUnknownAction, "unknown target "+target))
+		return
+	}
+
+	var res interface{}
+	var err error
+
+	decode := func(v interface{}) error {
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			return newKinesisErr(kinesisErrSerialization, err.Error())
+		}
+		return nil
+	}
+
+	switch action := strings.TrimPrefix(target, kinesisTargetPrefix); action {
+	case "CreateStream":
+		req := &kinesisCreateStreamReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.createStream(req)
+		}
+	case "DeleteStream":
+		req := &kinesisStreamReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.deleteStream(req)
+		}
+	case "DescribeStream":
+		req := &kinesisStreamReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.describeStream(req)
+		}
+	case "ListStreams":
+		req := &kinesisListStreamsReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.listStreams(req)
+		}
+	case "PutRecord":
+		req := &kinesisPutRecordReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.putRecord(r.Context(), req)
+		}
+	case "PutRecords":
+		req := &kinesisPutRecordsReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.putRecords(r.Context(), req)
+		}
+	case "GetShardIterator":
+		req := &kinesisGetShardIteratorReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.getShardIterator(req)
+		}
+	case "GetRecords":
+		req := &kinesisGetRecordsReq{}
+		if err = decode(req); err == nil {
+			res, err = kt.getRecords(r.Context(), req)
+		}
+	default:
+		err = newKinesisErr(kinesisErrUnknownAction, "unsupported action "+action)
+	}
+
+	if err != nil {
+		kinesisErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.Header().Set("X-Amzn-RequestId", uuid.New())
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		log.Printf("error: failed to write HTTP response %s", err)
+	}
+}
+
+type kinesisCreateStreamReq struct {
+	StreamName string
+	ShardCount int
+}
+
+func (kt *KinesisTransport) createStream(req *kinesisCreateStreamReq) (interface{}, error) {
+	if !kinesisStreamName.MatchString(req.StreamName) {
+		return nil, newKinesisErr(kinesisErrInvalidArgument, "invalid stream name")
+	}
+
+	if req.ShardCount > 1 {
+		return nil, newKinesisErr(kinesisErrInvalidArgument, "only single-shard streams are supported")
+	}
+
+	_, err := kt.nl.CreateTopic(req.StreamName, netlog.TopicSettings{})
+	if err != nil {
+		return nil, err
+	}
+
+	return struct{}{}, nil
+}
+
+type kinesisStreamReq struct {
+	StreamName string
+}
+
+func (kt *KinesisTransport) deleteStream(req *kinesisStreamReq) (interface{}, error) {
+	err := kt.nl.DeleteTopic(req.StreamName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return struct{}{}, nil
+}
+
+type kinesisHashKeyRange struct {
+	StartingHashKey string
+	EndingHashKey   string
+}
+
+type kinesisSequenceNumberRange struct {
+	StartingSequenceNumber string
+}
+
+type kinesisShard struct {
+	ShardID             string `json:"ShardId"`
+	HashKeyRange        kinesisHashKeyRange
+	SequenceNumberRange kinesisSequenceNumberRange
+}
+
+type kinesisStreamDescription struct {
+	StreamName    string
+	StreamARN     string
+	StreamStatus  string
+	Shards        []kinesisShard
+	HasMoreShards bool
+}
+
+func (kt *KinesisTransport) describeStream(req *kinesisStreamReq) (interface{}, error) {
+	t, err := kt.nl.Topic(req.StreamName)
+	if err != nil {
+		return nil, err
+	}
+
+	oldest, err := t.ParseOffset("oldest")
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		StreamDescription kinesisStreamDescription
+	}{kinesisStreamDescription{
+		StreamName:   t.Name(),
+		StreamARN:    "arn:aws:kinesis:local:000000000000:stream/" + t.Name(),
+		StreamStatus: "ACTIVE",
+		Shards: []kinesisShard{{
+			ShardID: kinesisShardID,
+			HashKeyRange: kinesisHashKeyRange{
+				StartingHashKey: "0",
+				EndingHashKey:   kinesisMaxHashKey,
+			},
+			SequenceNumberRange: kinesisSequenceNumberRange{
+				StartingSequenceNumber: strconv.FormatInt(oldest, 10),
+			},
+		}},
+	}}, nil
+}
+
+type kinesisListStreamsReq struct {
+	Limit                    int
+	ExclusiveStartStreamName string
+}
+
+func (kt *KinesisTransport) listStreams(req *kinesisListStreamsReq) (interface{}, error) {
+	names := kt.nl.TopicList()
+	sort.Strings(names)
+
+	if req.ExclusiveStartStreamName != "" {
+		i := sort.SearchStrings(names, req.ExclusiveStartStreamName)
+		if i < len(names) && names[i] == req.ExclusiveStartStreamName {
+			i++
+		}
+		names = names[i:]
+	}
+
+	more := false
+	if req.Limit > 0 && len(names) > req.Limit {
+		names = names[:req.Limit]
+		more = true
+	}
+
+	return struct {
+		StreamNames    []string
+		HasMoreStreams bool
+	}{names, more}, nil
+}
+
+type kinesisPutRecordReq struct {
+	StreamName   string
+	Data         []byte
+	PartitionKey string
+}
+
+type kinesisPutResult struct {
+	SequenceNumber string `json:",omitempty"`
+	ShardID        string `json:"ShardId,omitempty"`
+	ErrorCode      string `json:",omitempty"`
+	ErrorMessage   string `json:",omitempty"`
+}
+
+func (kt *KinesisTransport) putRecord(ctx context.Context, req *kinesisPutRecordReq) (interface{}, error) {
+	t, err := kt.nl.Topic(req.StreamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Data) == 0 {
+		return nil, newKinesisErr(kinesisErrInvalidArgument, "empty record data")
+	}
+
+	offset, err := t.WriteMessage(kinesisMessage(req.Data, req.PartitionKey))
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.WaitDurable(ctx, offset, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return kinesisPutResult{
+		SequenceNumber: strconv.FormatInt(offset, 10),
+		ShardID:        kinesisShardID,
+	}, nil
+}
+
+type kinesisPutRecordsReq struct {
+	StreamName string
+	Records    []kinesisPutRecordReq
+}
+
+func (kt *KinesisTransport) putRecords(ctx context.Context, req *kinesisPutRecordsReq) (interface{}, error) {
+	t, err := kt.nl.Topic(req.StreamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Records) == 0 || len(req.Records) > kinesisMaxPut {
+		return nil, newKinesisErr(kinesisErrInvalidArgument, "between 1 and 500 records must be provided")
+	}
+
+	var failed int
+	last := int64(-1)
+	results := make([]kinesisPutResult, len(req.Records))
+	for k, rec := range req.Records {
+		if len(rec.Data) == 0 {
+			failed++
+			results[k] = kinesisPutResult{ErrorCode: kinesisErrInvalidArgument, ErrorMessage: "empty record data"}
+			continue
+		}
+
+		offset, err := t.WriteMessage(kinesisMessage(rec.Data, rec.PartitionKey))
+		if err != nil {
+			failed++
+			results[k] = kinesisPutResult{ErrorCode: kinesisErrInternal, ErrorMessage: netlog.ExtErr(err).Error()}
+			continue
+		}
+
+		last = offset
+		results[k] = kinesisPutResult{
+			SequenceNumber: strconv.FormatInt(offset, 10),
+			ShardID:        kinesisShardID,
+		}
+	}
+
+	// a single wait covers all the records written
+	err = t.WaitDurable(ctx, last, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		FailedRecordCount int
+		Records           []kinesisPutResult
+	}{failed, results}, nil
+}
+
+type kinesisGetShardIteratorReq struct {
+	StreamName             string
+	ShardID                string `json:"ShardId"`
+	ShardIteratorType      string
+	StartingSequenceNumber string
+	Timestamp              float64
+}
+
+type kinesisIteratorRes struct {
+	ShardIterator string
+}
+
+func (kt *KinesisTransport) getShardIterator(req *kinesisGetShardIteratorReq) (interface{}, error) {
+	t, err := kt.nl.Topic(req.StreamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ShardID != kinesisShardID {
+		return nil, newKinesisErr(kinesisErrNotFound, "shard "+req.ShardID+" not found")
+	}
+
+	var offset int64
+	switch req.ShardIteratorType {
+	case "TRIM_HORIZON":
+		offset, err = t.ParseOffset("oldest")
+	case "LATEST":
+		offset, err = t.ParseOffset("end")
+	case "AT_SEQUENCE_NUMBER":
+		offset, err = strconv.ParseInt(req.StartingSequenceNumber, 10, 64)
+	case "AFTER_SEQUENCE_NUMBER":
+		offset, err = strconv.ParseInt(req.StartingSequenceNumber, 10, 64)
+		offset++
+	case "AT_TIMESTAMP":
+		// durations are parsed relative to now, Timestamp is in epoch seconds
+		offset, err = t.ParseOffset(fmt.Sprintf("%ds", kinesisSecondsAgo(req.Timestamp)))
+		if err != nil {
+			// nothing written after the timestamp
+			offset, err = t.ParseOffset("end")
+		}
+	default:
+		err = newKinesisErr(kinesisErrInvalidArgument, "invalid shard iterator type "+req.ShardIteratorType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if offset < 0 {
+		return nil, newKinesisErr(kinesisErrInvalidArgument, "invalid sequence number")
+	}
+
+	return kinesisIteratorRes{encodeShardIterator(t.Name(), offset)}, nil
+}
+
+type kinesisGetRecordsReq struct {
+	ShardIterator string
+	Limit         int
+}
+
+// kinesisMessage stores the partition key of a record as the key of the message.
+func kinesisMessage(data []byte, partitionKey string) netlog.Message {
+	if partitionKey == "" {
+		return netlog.MessageFromPayload(data)
+	}
+
+	return netlog.MessageWithMeta(data, netlog.MessageMeta{
+		Key:       []byte(partitionKey),
+		Timestamp: time.Now(),
+	})
+}
+
+type kinesisRecord struct {
+	SequenceNumber string
+	Data           []byte
+	PartitionKey   string
+}
+
+// getRecords reads up to Limit records without blocking. NetLog does not keep
+// arrival times, therefore MillisBehindLatest is always reported as 0.
+func (kt *KinesisTransport) getRecords(ctx context.Context, req *kinesisGetRecordsReq) (interface{}, error) {
+	name, offset, err := decodeShardIterator(req.ShardIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := kt.nl.Topic(name)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > kinesisMaxRecords {
+		limit = kinesisMaxRecords
+	}
+
+	// records behind the trim horizon are gone, start at the oldest
+	oldest, err := t.ParseOffset("oldest")
+	if err != nil {
+		return nil, err
+	}
+
+	if offset < oldest {
+		offset = oldest
+	}
+
+	end, err := t.ParseOffset("end")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]kinesisRecord, 0)
+	if offset < end {
+		sc, err := netlog.NewTopicScanner(t, uuid.New(), offset, false)
+		if err != nil {
+			return nil, err
+		}
+
+		defer logClose(sc)
+
+		// an already cancelled context makes
+		// the scanner return without waiting
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		for len(records) < limit {
+			m, o, err := sc.Scan(cctx)
+			if err == netlog.ErrEndOfTopic {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			records = append(records, kinesisRecord{
+				SequenceNumber: strconv.FormatInt(o, 10),
+				Data:           m.Payload(),
+				PartitionKey:   string(m.Key()),
+			})
+			offset = o + 1
+		}
+	}
+
+	return struct {
+		Records            []kinesisRecord
+		NextShardIterator  string
+		MillisBehindLatest int64
+	}{records, encodeShardIterator(name, offset), 0}, nil
+}
+
+// shard iterators are stateless, they encode the topic and the next offset to read
+func encodeShardIterator(topic string, offset int64) string {
+	return base64.URLEncoding.EncodeToString([]byte(topic + "/" + strconv.FormatInt(offset, 10)))
+}
+
+func decodeShardIterator(it string) (topic string, offset int64, err error) {
+	invalid := newKinesisErr(kinesisErrInvalidArgument, "invalid shard iterator")
+	data, err := base64.URLEncoding.DecodeString(it)
+	if err != nil {
+		return "", 0, invalid
+	}
+
+	i := strings.LastIndex(string(data), "/")
+	if i < 0 {
+		return "", 0, invalid
+	}
+
+	offset, err = strconv.ParseInt(string(data[i+1:]), 10, 64)
+	if err != nil || offset < 0 {
+		return "", 0, invalid
+	}
+
+	return string(data[:i]), offset, nil
+}
+
+func kinesisSecondsAgo(ts float64) int64 {
+	ago := time.Now().Unix() - int64(ts)
+	if ago < 0 {
+		return 0
+	}
+
+	return ago
+}
+
+// kinesisErrorResponse writes err in the format used by Kinesis mapping NetLog errors to their Kinesis equivalent.
+func kinesisErrorResponse(w http.ResponseWriter, err error) {
+	ke, ok := err.(*kinesisError)
+	if !ok {
+		e := netlog.ExtErr(err)
+		switch {
+		case e == netlog.ErrTopicNotFound:
+			ke = newKinesisErr(kinesisErrNotFound, e.Error())
+		case e == netlog.ErrTopicExists || e == netlog.ErrBusy:
+			ke = newKinesisErr(kinesisErrInUse, e.Error())
+		case e.StatusCode() < 500:
+			ke = newKinesisErr(kinesisErrInvalidArgument, e.Error())
+		default:
+			ke = newKinesisErr(kinesisErrInternal, e.Error())
+		}
+	}
+
+	log.Printf("warn: status %d -> %s", ke.status, ke)
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.WriteHeader(ke.status)
+	err = json.NewEncoder(w).Encode(ke)
+	if err != nil {
+		log.Printf("error: failed to write HTTP response %s", err)
+	}
+}
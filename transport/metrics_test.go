@@ -0,0 +1,59 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	ht := NewHTTPTransport(tempNetLog())
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", ht.MetricsHandler())
+	mux.Handle("/", ht)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	topicURL := fmt.Sprintf("%s/metrics_test", ts.URL)
+	r, err := http.Post(topicURL, "application/json", strings.NewReader(`{"batch_num_messages": 1}`))
+	panicOn(err)
+	logClose(r.Body)
+
+	for i := 0; i < 3; i++ {
+		r, err = http.Post(topicURL+"/payload", "application/octet-stream", bytes.NewReader([]byte("0123456789")))
+		panicOn(err)
+		logClose(r.Body)
+	}
+
+	r, err = http.Get(topicURL + "/payload/1")
+	panicOn(err)
+	logClose(r.Body)
+
+	r, err = http.Get(ts.URL + "/metrics")
+	panicOn(err)
+	body, err := ioutil.ReadAll(r.Body)
+	panicOn(err)
+	logClose(r.Body)
+
+	for _, line := range []string{
+		"# TYPE netlog_written_messages_total counter",
+		`netlog_written_messages_total{topic="metrics_test"} 3`,
+		`netlog_read_messages_total{topic="metrics_test"} 1`,
+		`netlog_segments{topic="metrics_test"} 1`,
+		`netlog_latest_offset{topic="metrics_test"} 2`,
+		`netlog_http_request_duration_seconds_count{method="POST",route="/:topic/payload"} 3`,
+		`netlog_http_request_duration_seconds_bucket{method="GET",route="/:topic/payload/:offset",le="+Inf"} 1`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Metric %q not found in:\n%s", line, body)
+		}
+	}
+}
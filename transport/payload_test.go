@@ -6,13 +6,19 @@ package transport
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/ninibe/netlog"
 )
 
 func TestSetGetPayload(t *testing.T) {
@@ -39,6 +45,10 @@ func TestSetGetPayload(t *testing.T) {
 		if err2 != nil {
 			t.Error(err2)
 		}
+
+		if offset := r.Header.Get("X-offset"); offset != fmt.Sprint(k) {
+			t.Errorf("Payload %d written at offset %q", k, offset)
+		}
 		_, _ = io.Copy(os.Stdout, r.Body)
 	}
 
@@ -77,3 +87,156 @@ func TestSetGetPayload(t *testing.T) {
 	_, err = http.DefaultClient.Do(req)
 	panicOn(err)
 }
+
+func TestWritePayloads(t *testing.T) {
+	ts := runTestHTTPServer()
+
+	// CREATE TOPIC
+	topicURL := fmt.Sprintf("%s/batch_test", ts.URL)
+	req, err := http.NewRequest("POST", topicURL, strings.NewReader(`{"compression_type": 2}`))
+	panicOn(err)
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err)
+	}
+	_, _ = io.Copy(os.Stdout, r.Body)
+
+	post := func(query string, body []byte) (int, OffsetsMsg) {
+		req2, err2 := http.NewRequest("POST", topicURL+"/payloads"+query, bytes.NewReader(body))
+		panicOn(err2)
+		r2, err2 := http.DefaultClient.Do(req2)
+		panicOn(err2)
+		defer logClose(r2.Body)
+
+		var om OffsetsMsg
+		if r2.StatusCode == http.StatusCreated {
+			panicOn(json.NewDecoder(r2.Body).Decode(&om))
+		}
+		return r2.StatusCode, om
+	}
+
+	// WRITE FRAMED MESSAGES
+	data := randDataSet(10, 512)
+	framed := &bytes.Buffer{}
+	for _, d := range data {
+		framed.Write(netlog.MessageFromPayload(d))
+	}
+
+	status, om := post("", framed.Bytes())
+	if status != http.StatusCreated || om.Offset != 0 || om.Last != 9 || om.Count != 10 {
+		t.Errorf("Unexpected response %d %+v", status, om)
+	}
+
+	// WRITE LINES
+	lines := []string{"first", "second", "", "third"}
+	status, om = post("?format=lines", []byte(strings.Join(lines, "\n")+"\n"))
+	if status != http.StatusCreated || om.Offset != 10 || om.Last != 12 || om.Count != 3 {
+		t.Errorf("Unexpected response %d %+v", status, om)
+	}
+
+	// WAIT UNTIL SYNCED
+	status, om = post("?format=lines&durability=synced", []byte("fourth\n"))
+	if status != http.StatusCreated || om.Offset != 13 {
+		t.Errorf("Unexpected response %d %+v", status, om)
+	}
+
+	if status, _ = post("?format=lines&durability=eventually", []byte("fifth\n")); status != http.StatusBadRequest {
+		t.Errorf("Invalid durability accepted with status %d", status)
+	}
+
+	// REJECT CORRUPT MESSAGES
+	corrupt := netlog.MessageFromPayload(data[0])
+	corrupt[len(corrupt)-1]++
+	if status, _ = post("", corrupt); status != http.StatusBadRequest {
+		t.Errorf("Corrupt message accepted with status %d", status)
+	}
+
+	if status, _ = post("", framed.Bytes()[:framed.Len()-1]); status != http.StatusBadRequest {
+		t.Errorf("Truncated message accepted with status %d", status)
+	}
+
+	// READ BACK
+	expected := append(data, []byte("first"), []byte("second"), []byte("third"), []byte("fourth"))
+	for k := range expected {
+		r, err = http.Get(fmt.Sprintf("%s/payload/%d", topicURL, k))
+		panicOn(err)
+		payload, err2 := ioutil.ReadAll(r.Body)
+		panicOn(err2)
+		if !bytes.Equal(expected[k], payload) {
+			t.Errorf("payload read error at %d:\n Expected: % x\n Actual: % x", k, expected[k], payload)
+		}
+	}
+}
+
+func TestPayloadMeta(t *testing.T) {
+	ts := runTestHTTPServer()
+
+	topicURL := fmt.Sprintf("%s/meta_test", ts.URL)
+	r, err := http.Post(topicURL, "", nil)
+	panicOn(err)
+	logClose(r.Body)
+
+	req, err := http.NewRequest("POST", topicURL+"/payload", strings.NewReader("data"))
+	panicOn(err)
+	req.Header.Set("X-Netlog-Key", "user-42")
+	req.Header.Set("X-Netlog-Timestamp", "2020-01-02T03:04:05.000000006Z")
+	req.Header.Set("X-Netlog-Header-Trace-Id", "abc")
+	r, err = http.DefaultClient.Do(req)
+	panicOn(err)
+	logClose(r.Body)
+
+	check := func(r *http.Response) {
+		defer logClose(r.Body)
+		payload, err2 := ioutil.ReadAll(r.Body)
+		panicOn(err2)
+		if string(payload) != "data" ||
+			r.Header.Get("X-Netlog-Key") != "user-42" ||
+			r.Header.Get("X-Netlog-Timestamp") != "2020-01-02T03:04:05.000000006Z" ||
+			r.Header.Get("X-Netlog-Header-Trace-Id") != "abc" {
+			t.Errorf("Unexpected response %q with headers %v", payload, r.Header)
+		}
+	}
+
+	r, err = http.Get(topicURL + "/payload/0")
+	panicOn(err)
+	check(r)
+
+	r, err = http.Post(topicURL+"/scanner?from=0", "", nil)
+	panicOn(err)
+	var msg IDMsg
+	panicOn(json.NewDecoder(r.Body).Decode(&msg))
+	logClose(r.Body)
+
+	r, err = http.Get(topicURL + "/scan?id=" + msg.ID)
+	panicOn(err)
+	// the checksum covers only the payload, not the key nor the headers
+	if crc := strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte("data"))), 10); r.Header.Get("X-crc32") != crc {
+		t.Errorf("X-crc32 %q instead of %q", r.Header.Get("X-crc32"), crc)
+	}
+	check(r)
+
+	req, err = http.NewRequest("POST", topicURL+"/payload", strings.NewReader("data"))
+	panicOn(err)
+	req.Header.Set("X-Netlog-Timestamp", "yesterday")
+	r, err = http.DefaultClient.Do(req)
+	panicOn(err)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusBadRequest {
+		t.Errorf("Invalid timestamp accepted with status %d", r.StatusCode)
+	}
+
+	// tombstones are the only empty payloads
+	for key, status := range map[string]int{"": http.StatusBadRequest, "user-42": http.StatusCreated} {
+		req, err = http.NewRequest("POST", topicURL+"/payload", nil)
+		panicOn(err)
+		if key != "" {
+			req.Header.Set("X-Netlog-Key", key)
+		}
+		r, err = http.DefaultClient.Do(req)
+		panicOn(err)
+		logClose(r.Body)
+		if r.StatusCode != status {
+			t.Errorf("Empty payload with key %q returned %d", key, r.StatusCode)
+		}
+	}
+}
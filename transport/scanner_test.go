@@ -87,6 +87,15 @@ func TestScanner(t *testing.T) {
 		}
 	}
 
+	r, err = http.Get(fmt.Sprintf("%s/scanner_test/scanners", ts.URL))
+	panicOn(err)
+	var infos []netlog.TScannerInfo
+	panicOn(json.NewDecoder(r.Body).Decode(&infos))
+	logClose(r.Body)
+	if len(infos) != 1 || infos[0].ID != si.ID || infos[0].Lag != 0 || infos[0].LastScan.IsZero() {
+		t.Errorf("Unexpected scanners listed %+v", infos)
+	}
+
 	// TODO test concurrent access
 
 	topicURL = fmt.Sprintf("%s/scanner_test", ts.URL)
@@ -95,3 +104,118 @@ func TestScanner(t *testing.T) {
 	_, err = http.DefaultClient.Do(req)
 	panicOn(err)
 }
+
+func TestAckScanner(t *testing.T) {
+	ts := runTestHTTPServer()
+
+	topicURL := fmt.Sprintf("%s/ack_scanner_test", ts.URL)
+	r, err := http.Post(topicURL, "", nil)
+	panicOn(err)
+	logClose(r.Body)
+
+	data := randDataSet(2, 128)
+	for k := range data {
+		r, err = http.Post(topicURL+"/payload", "", bytes.NewBuffer(data[k]))
+		panicOn(err)
+		logClose(r.Body)
+	}
+
+	r, err = http.Post(topicURL+"/scanner?from=0&ack=true&visibility=50ms", "", nil)
+	panicOn(err)
+
+	si := netlog.TScannerInfo{}
+	panicOn(json.NewDecoder(r.Body).Decode(&si))
+	logClose(r.Body)
+	if !si.Ack || !si.Persist {
+		t.Fatalf("Scanner not in ack mode %+v", si)
+	}
+
+	scan := func() string {
+		r2, err2 := http.Get(fmt.Sprintf("%s/scan?id=%s&wait=1s", topicURL, si.ID))
+		panicOn(err2)
+		defer logClose(r2.Body)
+		return r2.Header.Get("X-offset")
+	}
+
+	ack := func(offset string) int {
+		r2, err2 := http.Post(fmt.Sprintf("%s/scanner/ack?id=%s&offset=%s", topicURL, si.ID, offset), "", nil)
+		panicOn(err2)
+		logClose(r2.Body)
+		return r2.StatusCode
+	}
+
+	if o := scan(); o != "0" {
+		t.Errorf("Scanned offset %q", o)
+	}
+
+	if o := scan(); o != "1" {
+		t.Errorf("Scanned offset %q", o)
+	}
+
+	if status := ack("1"); status != http.StatusOK {
+		t.Errorf("Failed to ack: %d", status)
+	}
+
+	// offset 0 is delivered again
+	if o := scan(); o != "0" {
+		t.Errorf("Un-acked offset not redelivered, scanned %q", o)
+	}
+
+	if status := ack("5"); status != http.StatusBadRequest {
+		t.Errorf("Invalid ack accepted: %d", status)
+	}
+}
+
+func TestScanBatch(t *testing.T) {
+	ts := runTestHTTPServer()
+
+	topicURL := fmt.Sprintf("%s/scan_batch_test", ts.URL)
+	r, err := http.Post(topicURL, "", nil)
+	panicOn(err)
+	logClose(r.Body)
+
+	data := randDataSet(10, 100)
+	for k := range data {
+		r, err = http.Post(topicURL+"/payload", "", bytes.NewBuffer(data[k]))
+		panicOn(err)
+		logClose(r.Body)
+	}
+
+	r, err = http.Post(topicURL+"/scanner?from=0", "", nil)
+	panicOn(err)
+	si := netlog.TScannerInfo{}
+	panicOn(json.NewDecoder(r.Body).Decode(&si))
+	logClose(r.Body)
+
+	scan := func(query string) (string, []netlog.Message) {
+		r2, err2 := http.Get(fmt.Sprintf("%s/scan?id=%s&%s", topicURL, si.ID, query))
+		panicOn(err2)
+		defer logClose(r2.Body)
+		if r2.StatusCode != http.StatusOK {
+			t.Fatalf("Scan failed with status %d", r2.StatusCode)
+		}
+
+		offsets, msgs := readMessages(r2.Body)
+		if r2.Header.Get("X-count") != fmt.Sprint(len(msgs)) {
+			t.Errorf("Unexpected X-count %s for %d messages", r2.Header.Get("X-count"), len(msgs))
+		}
+
+		return fmt.Sprint(offsets), msgs
+	}
+
+	offsets, msgs := scan("max_messages=4")
+	if offsets != "[0 1 2 3]" || len(msgs) != 4 || !bytes.Equal(msgs[3].Payload(), data[3]) {
+		t.Errorf("Unexpected batch %q of %d messages", offsets, len(msgs))
+	}
+
+	// every message takes 109 bytes framed, the delta headers are not counted
+	offsets, msgs = scan("max_bytes=250")
+	if offsets != "[4 5]" || len(msgs) != 2 || !bytes.Equal(msgs[1].Payload(), data[5]) {
+		t.Errorf("Unexpected batch %q of %d messages", offsets, len(msgs))
+	}
+
+	offsets, msgs = scan("max_messages=100&wait=1s")
+	if offsets != "[6 7 8 9]" || len(msgs) != 4 {
+		t.Errorf("Unexpected batch %q of %d messages", offsets, len(msgs))
+	}
+}
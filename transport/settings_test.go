@@ -0,0 +1,59 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ninibe/netlog"
+)
+
+func TestUpdateSettings(t *testing.T) {
+	ts := runTestHTTPServer()
+	topicURL := fmt.Sprintf("%s/settings_test", ts.URL)
+
+	do := func(method, url, body string) *http.Response {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		panicOn(err)
+		r, err := http.DefaultClient.Do(req)
+		panicOn(err)
+		return r
+	}
+
+	r := do("PUT", topicURL+"/settings", `{}`)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 updating a missing topic, got %d", r.StatusCode)
+	}
+
+	r = do("POST", topicURL, `{"segment_size": 1024}`)
+	logClose(r.Body)
+
+	r = do("PUT", topicURL+"/settings", `{"cleanup_policy": "forever"}`)
+	logClose(r.Body)
+	if r.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid settings, got %d", r.StatusCode)
+	}
+
+	r = do("PUT", topicURL+"/settings", `{"segment_size": 2048, "durability": "flushed"}`)
+	var settings netlog.TopicSettings
+	panicOn(json.NewDecoder(r.Body).Decode(&settings))
+	logClose(r.Body)
+	if r.StatusCode != http.StatusOK || settings.SegSize != 2048 || settings.Durability != netlog.DurabilityFlushed {
+		t.Errorf("Unexpected update response %d %+v", r.StatusCode, settings)
+	}
+
+	r = do("GET", topicURL, "")
+	var info netlog.TopicInfo
+	panicOn(json.NewDecoder(r.Body).Decode(&info))
+	logClose(r.Body)
+	if info.Settings.SegSize != 2048 {
+		t.Errorf("Settings not updated %+v", info.Settings)
+	}
+}
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ninibe/netlog"
+)
+
+func TestStream(t *testing.T) {
+	ts := runTestHTTPServer()
+
+	// CREATE TOPIC
+	topicURL := fmt.Sprintf("%s/stream_test", ts.URL)
+	req, err := http.NewRequest("POST", topicURL, nil)
+	panicOn(err)
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err)
+	}
+	_, _ = io.Copy(os.Stdout, r.Body)
+
+	postURL := fmt.Sprintf("%s/stream_test/payload", ts.URL)
+	data := randDataSet(20, 1024)
+	write := func(data [][]byte) {
+		for k := range data {
+			req2, err2 := http.NewRequest("POST", postURL, bytes.NewBuffer(data[k]))
+			panicOn(err2)
+			r2, err2 := http.DefaultClient.Do(req2)
+			if err2 != nil {
+				t.Error(err2)
+			}
+			_, _ = io.Copy(os.Stdout, r2.Body)
+		}
+	}
+
+	// WRITE HALF THE DATA
+	write(data[:10])
+
+	// OPEN STREAM
+	streamURL := fmt.Sprintf("%s/stream_test/stream?from=0&max_offsets=4", ts.URL)
+	req, err = http.NewRequest("GET", streamURL, nil)
+	panicOn(err)
+	r, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.StatusCode != 200 {
+		t.Fatalf("Stream received status code %d", r.StatusCode)
+	}
+
+	// WRITE THE REST WHILE STREAMING
+	go write(data[10:])
+
+	br := bufio.NewReader(r.Body)
+	var offset int64
+	for offset < int64(len(data)) {
+		h, err2 := netlog.ReadDeltaHeader(br)
+		if err2 != nil {
+			t.Fatal(err2)
+		}
+
+		if h.Offset != offset || h.ODelta > 4 {
+			t.Fatalf("Invalid delta header %+v expected offset %d", h, offset)
+		}
+
+		for i := int64(0); i < h.ODelta; i++ {
+			m, err3 := netlog.ReadMessage(br)
+			if err3 != nil {
+				t.Fatal(err3)
+			}
+
+			if !bytes.Equal(data[offset], m.Payload()) {
+				t.Errorf("payload stream error on offset %d", offset)
+			}
+
+			offset++
+		}
+	}
+
+	logClose(r.Body)
+
+	// INVALID OFFSET
+	streamURL = fmt.Sprintf("%s/stream_test/stream?from=1000", ts.URL)
+	r, err = http.Get(streamURL)
+	panicOn(err)
+	if r.StatusCode != http.StatusNotFound {
+		t.Errorf("Stream from invalid offset received status code %d", r.StatusCode)
+	}
+
+	req, err = http.NewRequest("DELETE", topicURL+"?force=true", nil)
+	panicOn(err)
+	_, err = http.DefaultClient.Do(req)
+	panicOn(err)
+}
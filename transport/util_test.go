@@ -7,6 +7,7 @@ package transport
 import (
 	crand "crypto/rand"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http/httptest"
 	"os"
@@ -23,7 +24,7 @@ func init() {
 	colog.SetMinLevel(colog.LError)
 }
 
-func runTestHTTPServer() *httptest.Server {
+func runTestHTTPServer(opts ...HTTPOption) *httptest.Server {
 
 	rand.Seed(int64(time.Now().Nanosecond()))
 	datadir := filepath.Join(os.TempDir(), fmt.Sprintf("netlogtest-%d", rand.Int63()))
@@ -34,7 +35,23 @@ func runTestHTTPServer() *httptest.Server {
 	nl, err := netlog.NewNetLog(datadir, netlog.MonitorInterval(bd))
 	panicOn(err)
 
-	return httptest.NewServer(NewHTTPTransport(nl))
+	return httptest.NewServer(NewHTTPTransport(nl, opts...))
+}
+
+// readMessages reads the messages written by writeMessages and their offsets.
+func readMessages(r io.Reader) (offsets []int64, msgs []netlog.Message) {
+	for {
+		h, err := netlog.ReadDeltaHeader(r)
+		if err == io.EOF {
+			return offsets, msgs
+		}
+		panicOn(err)
+
+		m, err := netlog.ReadMessage(io.LimitReader(r, h.Size))
+		panicOn(err)
+		offsets = append(offsets, h.Offset)
+		msgs = append(msgs, m)
+	}
 }
 
 func randData(size int) []byte {